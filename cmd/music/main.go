@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	"github.com/swaggo/echo-swagger"
@@ -9,10 +12,16 @@ import (
 	"music-test-lib/config"
 	_ "music-test-lib/docs"
 	v1 "music-test-lib/internal/api/v1"
+	"music-test-lib/internal/musicinfo"
 	"music-test-lib/internal/repository"
 	"music-test-lib/internal/service"
 	"music-test-lib/pkg/db"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 const (
@@ -51,7 +60,8 @@ func main() {
 
 	dbConn, err := db.Connect(dbConfig, log)
 	if err != nil {
-		log.Error("failed to connect to database: %v", err)
+		log.Error("failed to connect to database", slog.Any("error", err))
+		os.Exit(1)
 	}
 	defer dbConn.Close()
 	log.Info("connect db success")
@@ -59,26 +69,139 @@ func main() {
 	makeMigrate(dbConfig, cfg.DataBase.FileMigrations, log)
 
 	repo := repository.NewSongRepository(dbConn)
-	songService := service.NewSongService(repo, log)
+	idempotencyRepo := repository.NewIdempotencyRepository(dbConn)
+	musicInfo, err := newMusicInfoClient(cfg.API, log.With(slog.String("component", "musicinfo")))
+	if err != nil {
+		log.Error("failed to configure music info providers", slog.Any("error", err))
+		os.Exit(1)
+	}
+	idempotencyCfg := service.IdempotencyConfig{
+		ReserveTimeout:  cfg.Idempotency.ReserveTimeout,
+		TTL:             cfg.Idempotency.KeyTTL,
+		CleanupInterval: cfg.Idempotency.CleanupInterval,
+	}
+	songService := service.NewSongService(repo, idempotencyRepo, idempotencyCfg, musicInfo, log, cursorSecret(cfg.Pagination.CursorSecret, log))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Фоновое получение данных о песнях, добавленных асинхронно
+	enricher := service.NewEnricher(repository.NewEnrichmentRepository(dbConn), musicInfo, service.EnrichmentConfig{
+		Workers:         cfg.Enrichment.Workers,
+		PollInterval:    cfg.Enrichment.PollInterval,
+		MaxAttempts:     cfg.Enrichment.MaxAttempts,
+		RetryBackoff:    cfg.Enrichment.RetryBackoff,
+		RetryBackoffMax: cfg.Enrichment.RetryBackoffMax,
+		Lease:           cfg.Enrichment.Lease,
+	}, log.With(slog.String("component", "enrichment")))
+	// Периодическое обновление устаревших данных песен
+	refresher := service.NewRefresher(songService, service.RefreshConfig{
+		MaxAge:    cfg.Refresh.MaxAge,
+		Interval:  cfg.Refresh.Interval,
+		BatchSize: cfg.Refresh.BatchSize,
+	}, log.With(slog.String("component", "refresh")))
+	// Удаление истёкших ключей идемпотентности
+	cleaner := service.NewIdempotencyCleaner(idempotencyRepo, idempotencyCfg, log.With(slog.String("component", "idempotency")))
+	var workers sync.WaitGroup
+	workers.Add(3)
+	go func() {
+		defer workers.Done()
+		enricher.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		refresher.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		cleaner.Run(ctx)
+	}()
 
 	e := echo.New()
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	v1.RegisterRoutes(e, log, songService, cfg)
-
-	e.Logger.Fatal(e.Start(cfg.HTTPServer.Address))
+	v1.RegisterRoutes(e, log, v1.Services{
+		Songs:     songService,
+		Artists:   service.NewArtistService(repository.NewArtistRepository(dbConn)),
+		Albums:    service.NewAlbumService(repository.NewAlbumRepository(dbConn)),
+		Genres:    service.NewGenreService(repository.NewGenreRepository(dbConn)),
+		Tags:      service.NewTagService(repository.NewTagRepository(dbConn)),
+		Playlists: service.NewPlaylistService(repository.NewPlaylistRepository(dbConn)),
+	}, cfg)
+
+	go func() {
+		if err := e.Start(cfg.HTTPServer.Address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to start server", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to shut down server", slog.Any("error", err))
+	}
+	workers.Wait()
 }
 
 func makeMigrate(cfg *db.Config, filePath string, log *slog.Logger) {
 	if err := db.Migrate(cfg, filePath, log); err != nil {
-		log.Error("failed to run migrations: ", err)
+		log.Error("failed to run migrations", slog.Any("error", err))
 		os.Exit(1)
 	} else {
 		log.Info("migration success")
 	}
 }
 
+// newMusicInfoClient создаёт клиент, объединяющий сведения из настроенных источников.
+func newMusicInfoClient(cfg config.API, log *slog.Logger) (*musicinfo.MultiClient, error) {
+	httpCfg := musicinfo.Config{
+		Timeout:          cfg.MusicInfoTimeout,
+		MaxRetries:       cfg.MusicInfoMaxRetries,
+		RetryBackoff:     cfg.MusicInfoRetryBackoff,
+		RetryBackoffMax:  cfg.MusicInfoRetryBackoffMax,
+		BreakerThreshold: cfg.MusicInfoBreakerThreshold,
+		BreakerCooldown:  cfg.MusicInfoBreakerCooldown,
+	}
+	specs := cfg.MusicInfoProviders
+	if len(specs) == 0 {
+		if cfg.MusicInfoURL == "" {
+			return nil, errors.New("API_MUSIC_INFO_URL or API_MUSIC_INFO_PROVIDERS must be set")
+		}
+		specs = []string{"api=" + cfg.MusicInfoURL}
+	}
+
+	providers := make([]musicinfo.Provider, 0, len(specs))
+	for _, spec := range specs {
+		provider, err := musicinfo.ParseProvider(spec, httpCfg, log)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	prefer, err := musicinfo.ParseMergeRules(cfg.MusicInfoMerge)
+	if err != nil {
+		return nil, err
+	}
+	return musicinfo.NewMultiClient(providers, prefer, log)
+}
+
+func cursorSecret(secret string, log *slog.Logger) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+	log.Warn("PAGINATION_CURSOR_SECRET is not set, cursors will be invalidated on restart")
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		log.Error("failed to generate cursor secret", slog.Any("error", err))
+		os.Exit(1)
+	}
+	return random
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"music-test-lib/internal/musicinfo"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fixture - ответ заглушки для одной песни.
+// Файлы фикстур содержат одну запись или список записей в формате JSON или YAML.
+type fixture struct {
+	Group       string `yaml:"group"`
+	Song        string `yaml:"song"`
+	ReleaseDate string `yaml:"releaseDate"`
+	Text        string `yaml:"text"`
+	Link        string `yaml:"link"`
+}
+
+// fixtureKey возвращает ключ поиска песни без учёта регистра и лишних пробелов.
+func fixtureKey(group, song string) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), " "))
+	}
+	return normalize(group) + "\x00" + normalize(song)
+}
+
+// loadFixtures читает все файлы *.json, *.yaml и *.yml из каталога dir.
+func loadFixtures(dir string) (map[string]musicinfo.SongInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	songs := map[string]musicinfo.SongInfo{}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		fixtures, err := readFixtureFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, f := range fixtures {
+			if f.Group == "" || f.Song == "" {
+				return nil, fmt.Errorf("%s: group and song are required", path)
+			}
+			songs[fixtureKey(f.Group, f.Song)] = musicinfo.SongInfo{
+				ReleaseDate: f.ReleaseDate,
+				Text:        f.Text,
+				Link:        f.Link,
+			}
+		}
+	}
+	return songs, nil
+}
+
+// readFixtureFile читает файл с одной фикстурой или списком фикстур.
+// JSON является подмножеством YAML, поэтому оба формата разбираются одинаково.
+func readFixtureFile(path string) ([]fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []fixture
+	if err := yaml.Unmarshal(data, &list); err == nil {
+		return list, nil
+	}
+	var single fixture
+	if err := yaml.Unmarshal(data, &single); err != nil {
+		return nil, err
+	}
+	return []fixture{single}, nil
+}
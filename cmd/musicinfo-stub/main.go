@@ -0,0 +1,114 @@
+// Команда musicinfo-stub - локальная заглушка внешнего API сведений о песнях
+// (GET /info?group=&song=) для разработки и тестирования без настоящего API.
+// Ответы берутся из каталога фикстур; задержки, ошибки и некорректные
+// ответы можно включить переменными окружения или для отдельного запроса
+// заголовком X-Stub-Fault.
+package main
+
+import (
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/labstack/echo/v4"
+	"log"
+	"log/slog"
+	"math/rand/v2"
+	"music-test-lib/internal/musicinfo"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	Address     string `env:"STUB_ADDRESS" env-default:"0.0.0.0:8081"`
+	FixturesDir string `env:"STUB_FIXTURES_DIR" env-default:"fixtures/musicinfo"`
+	// Задержка перед каждым ответом
+	Latency time.Duration `env:"STUB_LATENCY" env-default:"0s"`
+	// Доля запросов (0..1), на которые возвращается ErrorStatus
+	ErrorRate   float64 `env:"STUB_ERROR_RATE" env-default:"0"`
+	ErrorStatus int     `env:"STUB_ERROR_STATUS" env-default:"500"`
+	// Доля запросов (0..1), на которые возвращается некорректный JSON
+	MalformedRate float64 `env:"STUB_MALFORMED_RATE" env-default:"0"`
+}
+
+// Значения заголовка X-Stub-Fault для отдельного запроса
+const (
+	headerFault    = "X-Stub-Fault"
+	faultError     = "error"     // ответ ErrorStatus (или статус из заголовка X-Stub-Status)
+	faultMalformed = "malformed" // некорректный JSON
+	faultSlow      = "slow"      // задержка из заголовка X-Stub-Latency, например 3s
+)
+
+type server struct {
+	cfg   Config
+	songs map[string]musicinfo.SongInfo
+	log   *slog.Logger
+}
+
+func main() {
+	var cfg Config
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации: %s", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	songs, err := loadFixtures(cfg.FixturesDir)
+	if err != nil {
+		logger.Error("failed to load fixtures", slog.Any("error", err))
+		os.Exit(1)
+	}
+	s := &server{cfg: cfg, songs: songs, log: logger}
+	logger.Info("fixtures loaded", slog.String("dir", cfg.FixturesDir), slog.Int("songs", len(s.songs)))
+
+	e := echo.New()
+	e.HideBanner = true
+	e.GET("/info", s.info)
+
+	logger.Info("starting music info stub", slog.String("address", cfg.Address))
+	e.Logger.Fatal(e.Start(cfg.Address))
+}
+
+// info возвращает сведения о песне из фикстур.
+func (s *server) info(c echo.Context) error {
+	group, song := c.QueryParam("group"), c.QueryParam("song")
+	fault := c.Request().Header.Get(headerFault)
+	s.log.Info("info requested", slog.String("group", group), slog.String("song", song), slog.String("fault", fault))
+
+	latency := s.cfg.Latency
+	if fault == faultSlow {
+		if d, err := time.ParseDuration(c.Request().Header.Get("X-Stub-Latency")); err == nil {
+			latency = d
+		}
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+
+	if fault == faultError || chance(s.cfg.ErrorRate) {
+		status := s.cfg.ErrorStatus
+		if code, err := strconv.Atoi(c.Request().Header.Get("X-Stub-Status")); err == nil {
+			status = code
+		}
+		return c.String(status, http.StatusText(status))
+	}
+	if fault == faultMalformed || chance(s.cfg.MalformedRate) {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(`{"releaseDate": "16.07.2006", "text": `))
+	}
+
+	if group == "" || song == "" {
+		return c.String(http.StatusBadRequest, "group and song are required")
+	}
+	info, ok := s.songs[fixtureKey(group, song)]
+	if !ok {
+		return c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	}
+	return c.JSON(http.StatusOK, info)
+}
+
+// chance возвращает true с вероятностью p.
+func chance(p float64) bool {
+	return p > 0 && rand.Float64() < p
+}
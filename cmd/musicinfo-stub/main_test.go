@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"io"
+	"log/slog"
+	"music-test-lib/internal/musicinfo"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, cfg Config) *server {
+	t.Helper()
+	songs, err := loadFixtures(filepath.Join("..", "..", "fixtures", "musicinfo"))
+	if err != nil {
+		t.Fatalf("loadFixtures: %v", err)
+	}
+	return &server{cfg: cfg, songs: songs, log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+// get выполняет GET /info с заданными группой, песней и заголовками.
+func get(t *testing.T, s *server, group, song string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	q := url.Values{"group": {group}, "song": {song}}
+	req := httptest.NewRequest(http.MethodGet, "/info?"+q.Encode(), nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	if err := s.info(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("info: %v", err)
+	}
+	return rec
+}
+
+func TestInfoFixtureLookup(t *testing.T) {
+	s := newTestServer(t, Config{ErrorStatus: http.StatusInternalServerError})
+	tests := []struct {
+		name        string
+		group, song string
+		wantStatus  int
+		wantRelease string
+	}{
+		{"yaml fixture", "Muse", "Uprising", http.StatusOK, "07.09.2009"},
+		{"json fixture", "Guns N' Roses", "Sweet Child O' Mine", http.StatusOK, "17.08.1988"},
+		{"case and spaces are ignored", "  muse ", "supermassive   BLACK hole", http.StatusOK, "16.07.2006"},
+		{"unknown song", "Muse", "Starlight", http.StatusNotFound, ""},
+		{"missing song", "Muse", "", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, s, tt.group, tt.song, nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var info musicinfo.SongInfo
+			if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if info.ReleaseDate != tt.wantRelease || info.Text == "" || info.Link == "" {
+				t.Errorf("info = %+v, want release date %q with text and link", info, tt.wantRelease)
+			}
+		})
+	}
+}
+
+func TestInfoFaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           Config
+		header        map[string]string
+		wantStatus    int
+		wantMalformed bool
+	}{
+		{name: "no fault", wantStatus: http.StatusOK},
+		{name: "error header", header: map[string]string{headerFault: faultError}, wantStatus: http.StatusInternalServerError},
+		{
+			name:       "error header with status",
+			header:     map[string]string{headerFault: faultError, "X-Stub-Status": "503"},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "invalid status is ignored",
+			header:     map[string]string{headerFault: faultError, "X-Stub-Status": "busy"},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{name: "malformed header", header: map[string]string{headerFault: faultMalformed}, wantStatus: http.StatusOK, wantMalformed: true},
+		{name: "unknown fault", header: map[string]string{headerFault: "explode"}, wantStatus: http.StatusOK},
+		{name: "error rate", cfg: Config{ErrorRate: 1, ErrorStatus: http.StatusBadGateway}, wantStatus: http.StatusBadGateway},
+		{name: "malformed rate", cfg: Config{MalformedRate: 1}, wantStatus: http.StatusOK, wantMalformed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cfg.ErrorStatus == 0 {
+				tt.cfg.ErrorStatus = http.StatusInternalServerError
+			}
+			rec := get(t, newTestServer(t, tt.cfg), "Muse", "Uprising", tt.header)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var info musicinfo.SongInfo
+			err := json.Unmarshal(rec.Body.Bytes(), &info)
+			if tt.wantMalformed && err == nil {
+				t.Errorf("body %s is valid JSON, want malformed", rec.Body)
+			} else if !tt.wantMalformed && err != nil {
+				t.Errorf("decode response: %v", err)
+			}
+		})
+	}
+}
+
+func TestInfoSlowFault(t *testing.T) {
+	s := newTestServer(t, Config{ErrorStatus: http.StatusInternalServerError})
+	start := time.Now()
+	rec := get(t, s, "Muse", "Uprising", map[string]string{headerFault: faultSlow, "X-Stub-Latency": "50ms"})
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("response after %v, want at least 50ms", elapsed)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	write := func(t *testing.T, dir, name, data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("single and list files", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "one.json", `{"group": "Muse", "song": "Uprising", "releaseDate": "07.09.2009"}`)
+		write(t, dir, "many.yml", "- group: Queen\n  song: Bicycle Race\n- group: Queen\n  song: Innuendo\n")
+		write(t, dir, "notes.txt", "not a fixture")
+		songs, err := loadFixtures(dir)
+		if err != nil {
+			t.Fatalf("loadFixtures: %v", err)
+		}
+		if len(songs) != 3 {
+			t.Fatalf("loaded %d songs, want 3", len(songs))
+		}
+		if got := songs[fixtureKey("MUSE", "uprising")].ReleaseDate; got != "07.09.2009" {
+			t.Errorf("release date = %q, want 07.09.2009", got)
+		}
+	})
+	t.Run("fixture without song", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "bad.yaml", "group: Muse\n")
+		if _, err := loadFixtures(dir); err == nil {
+			t.Error("loadFixtures() error = nil, want error")
+		}
+	})
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := loadFixtures(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("loadFixtures() error = nil, want error")
+		}
+	})
+}
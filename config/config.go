@@ -3,13 +3,18 @@ package config
 import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
+	"time"
 )
 
 type Config struct {
-	Env        string `env:"ENV" env-default:"development"`
-	HTTPServer HTTPServer
-	DataBase   DataBase
-	API        API
+	Env         string `env:"ENV" env-default:"development"`
+	HTTPServer  HTTPServer
+	DataBase    DataBase
+	API         API
+	Pagination  Pagination
+	Enrichment  Enrichment
+	Refresh     Refresh
+	Idempotency Idempotency
 }
 
 type DataBase struct {
@@ -23,10 +28,59 @@ type DataBase struct {
 
 type HTTPServer struct {
 	Address string `env:"HTTP_SERVER_ADDRESS" env-default:"0.0.0.0:8080"`
+	// Требовать заголовок If-Match при изменении и удалении песни
+	RequireIfMatch bool `env:"HTTP_REQUIRE_IF_MATCH" env-default:"false"`
 }
 
 type API struct {
-	MusicInfoURL string `env:"API_MUSIC_INFO_URL" env-required:"true"`
+	// Адрес единственного источника api, если API_MUSIC_INFO_PROVIDERS не заданы
+	MusicInfoURL              string        `env:"API_MUSIC_INFO_URL"`
+	MusicInfoTimeout          time.Duration `env:"API_MUSIC_INFO_TIMEOUT" env-default:"5s"`
+	MusicInfoMaxRetries       int           `env:"API_MUSIC_INFO_MAX_RETRIES" env-default:"3"`
+	MusicInfoRetryBackoff     time.Duration `env:"API_MUSIC_INFO_RETRY_BACKOFF" env-default:"200ms"`
+	MusicInfoRetryBackoffMax  time.Duration `env:"API_MUSIC_INFO_RETRY_BACKOFF_MAX" env-default:"5s"`
+	MusicInfoBreakerThreshold int           `env:"API_MUSIC_INFO_BREAKER_THRESHOLD" env-default:"5"`
+	MusicInfoBreakerCooldown  time.Duration `env:"API_MUSIC_INFO_BREAKER_COOLDOWN" env-default:"30s"`
+
+	// Источники сведений о песнях в порядке приоритета: имя=адрес через запятую,
+	// например api=http://host/info,files=file:///var/lib/lyrics
+	MusicInfoProviders []string `env:"API_MUSIC_INFO_PROVIDERS" env-separator:","`
+	// Предпочтительные источники полей: поле=имя через запятую, например lyrics=files
+	MusicInfoMerge []string `env:"API_MUSIC_INFO_MERGE" env-separator:","`
+}
+
+type Pagination struct {
+	// Секрет для подписи курсоров. Если не задан, генерируется при запуске,
+	// и курсоры перестают быть действительными после перезапуска сервиса.
+	CursorSecret string `env:"PAGINATION_CURSOR_SECRET"`
+}
+
+// Enrichment - настройки фонового получения данных о песнях, добавленных асинхронно.
+type Enrichment struct {
+	Workers         int           `env:"ENRICHMENT_WORKERS" env-default:"2"`
+	PollInterval    time.Duration `env:"ENRICHMENT_POLL_INTERVAL" env-default:"2s"`
+	MaxAttempts     int           `env:"ENRICHMENT_MAX_ATTEMPTS" env-default:"5"`
+	RetryBackoff    time.Duration `env:"ENRICHMENT_RETRY_BACKOFF" env-default:"30s"`
+	RetryBackoffMax time.Duration `env:"ENRICHMENT_RETRY_BACKOFF_MAX" env-default:"30m"`
+	Lease           time.Duration `env:"ENRICHMENT_LEASE" env-default:"2m"`
+}
+
+// Refresh - настройки периодического обновления данных песен из внешнего API.
+type Refresh struct {
+	// Возраст данных, после которого они запрашиваются заново (0 - не обновлять)
+	MaxAge time.Duration `env:"REFRESH_MAX_AGE" env-default:"720h"`
+	// Интервал между проверками (0 - не обновлять)
+	Interval  time.Duration `env:"REFRESH_INTERVAL" env-default:"1h"`
+	BatchSize int           `env:"REFRESH_BATCH_SIZE" env-default:"50"`
+}
+
+// Idempotency - настройки хранения ключей идемпотентности запросов на добавление песни.
+type Idempotency struct {
+	// Через сколько ключ незавершённого запроса считается брошенным
+	ReserveTimeout time.Duration `env:"IDEMPOTENCY_RESERVE_TIMEOUT" env-default:"1m"`
+	// Время хранения ключа (0 - хранить бессрочно)
+	KeyTTL          time.Duration `env:"IDEMPOTENCY_KEY_TTL" env-default:"24h"`
+	CleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"1h"`
 }
 
 func MustLoad() *Config {
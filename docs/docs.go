@@ -15,52 +15,40 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
+        "/albums": {
             "get": {
-                "description": "Возвращает список песен с возможностью фильтрации по всем полям (группа, название, дата выпуска, текст) и пагинацией.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает альбомы в порядке дат релиза; альбомы без даты - в конце.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получить список песен",
+                "summary": "Получить список альбомов",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Название группы",
-                        "name": "group_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Название песни",
-                        "name": "song_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата релиза (формат: YYYY-MM-DD)",
-                        "name": "release_date",
+                        "description": "Часть названия альбома",
+                        "name": "title",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Часть текста песни",
-                        "name": "lyrics",
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "artist_id",
                         "in": "query"
                     },
                     {
                         "type": "integer",
+                        "default": 1,
                         "description": "Номер страницы",
                         "name": "page",
                         "in": "query"
                     },
                     {
+                        "maximum": 100,
                         "type": "integer",
+                        "default": 10,
                         "description": "Количество записей на странице",
                         "name": "limit",
                         "in": "query"
@@ -68,18 +56,15 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список песен",
+                        "description": "Страница списка альбомов",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Song"
-                            }
+                            "$ref": "#/definitions/v1.AlbumListResponse"
                         }
                     },
                     "400": {
                         "description": "Некорректные параметры запроса",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
                         }
                     },
                     "500": {
@@ -91,7 +76,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Добавляет новую песню в библиотеку и получает информацию о песне из внешнего API.",
+                "description": "Добавляет альбом без треков; треки задаются через PUT /albums/{id}/tracks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -99,35 +84,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Добавить новую песню",
+                "summary": "Добавить альбом",
                 "parameters": [
                     {
-                        "description": "Песня (группа и название)",
-                        "name": "song",
+                        "description": "Альбом",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.AddSongRequest"
+                            "$ref": "#/definitions/v1.AlbumRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Песня добавлена с детальной информацией",
+                        "description": "Альбом добавлен",
                         "schema": {
-                            "$ref": "#/definitions/domain.Song"
+                            "$ref": "#/definitions/domain.Album"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес добавленного альбома: /albums/{id}"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные песни",
+                        "description": "Некорректные данные альбома или исполнитель не найден",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Не удалось добавить песню",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -135,49 +126,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}": {
+        "/albums/{id}": {
             "get": {
-                "description": "Возвращает текст песни с возможностью пагинации по куплетам.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает альбом с треками в порядке номеров дисков и треков.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получить текст песни",
+                "summary": "Получить альбом",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID песни",
+                        "type": "integer",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Номер куплета",
-                        "name": "verse",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Текст песни",
+                        "description": "Альбом",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/domain.Album"
                         }
                     },
                     "400": {
-                        "description": "Некорректный ID песни",
+                        "description": "Некорректный ID альбома",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Песня не найдена",
+                        "description": "Альбом не найден",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -191,7 +173,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Обновляет информацию о песне, включая название группы, название песни, текст, дату релиза и ссылку.",
+                "description": "Заменяет название, исполнителя, дату релиза и обложку альбома; треки не изменяются.",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,42 +181,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Изменить данные песни",
+                "summary": "Изменить альбом",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID песни",
+                        "type": "integer",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Новая информация о песне",
-                        "name": "song",
+                        "description": "Новые данные альбома",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateSongRequest"
+                            "$ref": "#/definitions/v1.AlbumRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Данные песни обновлены",
+                        "description": "Альбом изменён",
                         "schema": {
-                            "$ref": "#/definitions/v1.SuccessResponse"
+                            "$ref": "#/definitions/domain.Album"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные песни",
+                        "description": "Некорректные данные альбома или исполнитель не найден",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Песня не найдена",
+                        "description": "Альбом не найден",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -248,15 +230,15 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Удаляет песню по ее ID.",
+                "description": "Удаляет альбом и его треки; песни остаются в библиотеке.",
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Удалить песню",
+                "summary": "Удалить альбом",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID песни",
+                        "type": "integer",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -264,89 +246,3785 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Песня удалена",
+                        "description": "Альбом удалён",
                         "schema": {
                             "$ref": "#/definitions/v1.SuccessResponse"
                         }
                     },
+                    "400": {
+                        "description": "Некорректный ID альбома",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Песня не найдена",
+                        "description": "Альбом не найден",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Не удалось удалить песню",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Song": {
-            "description": "Модель данных песни.",
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string",
-                    "example": "Muse"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "1"
-                },
-                "link": {
-                    "type": "string",
-                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
-                },
-                "lyrics": {
-                    "type": "string",
-                    "example": "Ooh baby, don't you know I suffer? ..."
-                },
-                "release_date": {
-                    "type": "string",
-                    "example": "2006-07-16"
-                },
-                "title": {
-                    "type": "string",
-                    "example": "Supermassive Black Hole"
-                }
-            }
         },
-        "v1.AddSongRequest": {
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string",
-                    "example": "Muse"
-                },
-                "song": {
-                    "type": "string",
-                    "example": "Supermassive Black Hole"
+        "/albums/{id}/tracks": {
+            "put": {
+                "description": "Заменяет список треков альбома целиком. Номера треков не обязаны идти подряд,\nно позиция (диск, номер) и песня в альбоме не должны повторяться.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Задать треки альбома",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Треки альбома",
+                        "name": "tracks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AlbumTracksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Альбом с новыми треками",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные треки или песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "v1.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Описание ошибки"
+        "/artists": {
+            "get": {
+                "description": "Возвращает исполнителей в алфавитном порядке с количеством их песен.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получить список исполнителей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Часть названия исполнителя",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница списка исполнителей",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ArtistListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер страницы",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Добавить исполнителя",
+                "parameters": [
+                    {
+                        "description": "Исполнитель",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ArtistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Исполнитель добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Artist"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес добавленного исполнителя: /artists/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное название исполнителя",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "v1.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
+        "/artists/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получить исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Исполнитель",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID исполнителя",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Изменяет название исполнителя; название группы меняется во всех его песнях.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Переименовать исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название исполнителя",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ArtistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Исполнитель переименован",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или название исполнителя",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет исполнителя, у которого нет песен и альбомов.",
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Удалить исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Исполнитель удалён",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID исполнителя",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя есть песни или альбомы",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/songs": {
+            "get": {
+                "description": "Возвращает песни исполнителя с теми же фильтрами, сортировкой и пагинацией, что и GET /songs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получить песни исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию и тексту песни",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название песни",
+                        "name": "song_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка, как в GET /songs",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница списка песен исполнителя",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Возвращает жанры верхнего уровня с вложенными поджанрами в алфавитном порядке.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получить жанры",
+                "responses": {
+                    "200": {
+                        "description": "Дерево жанров",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Genre"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Добавить жанр",
+                "parameters": [
+                    {
+                        "description": "Жанр",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.GenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Жанр добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Genre"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес добавленного жанра: /genres/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные жанра или родительский жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получить жанр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанр с поджанрами",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID жанра",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Изменяет название жанра и переносит его в другой родительский жанр\n(пустой parent_id - на верхний уровень) вместе с поджанрами.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Изменить жанр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные жанра",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.GenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанр изменён",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные жанра, родительский жанр не найден или является поджанром",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет жанр без поджанров; у песен этот жанр снимается.",
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Удалить жанр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанр удалён",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID жанра",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У жанра есть поджанры",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists": {
+            "get": {
+                "description": "Возвращает публичные плейлисты и плейлисты пользователя из заголовка X-User-ID,\nначиная с недавно изменённых.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получить список плейлистов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Владелец плейлистов",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница списка плейлистов",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PlaylistListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер страницы",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создаёт пустой плейлист пользователя из заголовка X-User-ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Создать плейлист",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Плейлист",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Плейлист создан",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес созданного плейлиста: /playlists/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные плейлиста",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "description": "Возвращает публичный плейлист или плейлист пользователя с записями в порядке позиций.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получить плейлист",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID плейлиста",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет название, описание и видимость плейлиста; записи не изменяются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Изменить плейлист",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные плейлиста",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист изменён",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные плейлиста",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Плейлист принадлежит другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет плейлист с записями; песни остаются в библиотеке.",
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Удалить плейлист",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист удалён",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID плейлиста",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Плейлист принадлежит другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/duplicate": {
+            "post": {
+                "description": "Копирует свой или публичный плейлист со всеми записями в новый приватный плейлист\nпользователя из заголовка X-User-ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Скопировать плейлист",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Название копии",
+                        "name": "playlist",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DuplicatePlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Копия плейлиста",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес копии: /playlists/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или название",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries": {
+            "post": {
+                "description": "Добавляет песню на указанную позицию (по умолчанию - в конец); записи с этой позиции\nсдвигаются вниз. Одна песня может входить в плейлист несколько раз.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Добавить песню в плейлист",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Песня и позиция",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PlaylistEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист с новой записью",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная позиция или песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Плейлист принадлежит другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В плейлисте максимальное количество записей",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entry_id}": {
+            "delete": {
+                "description": "Удаляет запись; следующие записи сдвигаются вверх.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Удалить запись из плейлиста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи плейлиста",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист без записи",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Плейлист принадлежит другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или запись не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entry_id}/move": {
+            "post": {
+                "description": "Переносит запись на указанную позицию; записи между старой и новой позицией сдвигаются.\nОдновременные изменения одного плейлиста выполняются по очереди.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Переместить запись плейлиста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи плейлиста",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая позиция",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MoveEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист с новым порядком записей",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или позиция",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не задан заголовок X-User-ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Плейлист принадлежит другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или запись не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Возвращает список песен с возможностью фильтрации по всем полям (группа, название, дата выпуска, текст), сортировки и пагинацией.\nБез параметра sort песни упорядочиваются по id, а при полнотекстовом поиске (q) - по убыванию релевантности.\nСортировка по relevance доступна только вместе с q. Песни без даты релиза при сортировке по release_date считаются самыми поздними.\nПри поиске по q каждая песня содержит релевантность (rank) и фрагменты текста с выделенными совпадениями (snippet).\nПри fuzzy=true group_name и song_name сравниваются по сходству триграмм, песни содержат оценку сходства (similarity) и по умолчанию упорядочиваются по ней.\nЕсли точных совпадений по group_name/song_name нет, в did_you_mean возвращаются похожие названия из библиотеки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить список песен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по группе, названию и тексту песни (синтаксис websearch_to_tsquery: слова, фразы в кавычках, or, -исключение)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "group_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "Режим сравнения названия группы",
+                        "name": "group_name_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название песни",
+                        "name": "song_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "Режим сравнения названия песни",
+                        "name": "song_name_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата релиза (формат: YYYY-MM-DD)",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата релиза не раньше (формат: YYYY-MM-DD)",
+                        "name": "release_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата релиза не позже (формат: YYYY-MM-DD)",
+                        "name": "release_date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1997,
+                        "description": "Год релиза",
+                        "name": "release_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1990s",
+                        "description": "Десятилетие релиза",
+                        "name": "decade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "artist_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "album_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя, участвующего в песне (в любой роли или в роли credit_role)",
+                        "name": "credited_artist_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "primary",
+                            "featured",
+                            "composer",
+                            "lyricist",
+                            "producer"
+                        ],
+                        "type": "string",
+                        "description": "Роль участника песни",
+                        "name": "credit_role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Жанры (ID или названия): песня подходит, если у неё есть любой из них",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Учитывать поджанры жанров из genre",
+                        "name": "include_subgenres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Теги",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Песня должна иметь любой из тегов (any) или все теги (all)",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Нечёткий поиск по group_name и song_name с учётом опечаток",
+                        "name": "fuzzy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Часть текста песни",
+                        "name": "lyrics",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "Режим сравнения текста песни",
+                        "name": "lyrics_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-release_date,group_name",
+                        "description": "Сортировка: поля через запятую, минус - по убыванию (id, group_name, song_name, release_date, link, relevance, similarity)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor из предыдущего ответа); при наличии page не учитывается",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница списка песен",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет новую песню в библиотеку и получает информацию о песне из внешнего API.\nВ асинхронном режиме (async=true или заголовок Prefer: respond-async) песня сохраняется сразу\nсо статусом enrichment_status = pending, а информация из внешнего API запрашивается в фоне;\nрезультат можно узнать по адресу из заголовка Location.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Добавить новую песню",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом вернёт уже добавленную песню",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond-async - добавить песню асинхронно",
+                        "name": "Prefer",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Добавить песню асинхронно",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Песня (группа и название)",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AddSongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Песня добавлена с детальной информацией",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true, если возвращён результат предыдущего запроса с тем же ключом"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес добавленной песни: /songs/{id}"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Песня добавлена, информация из внешнего API будет получена позже",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true, если возвращён результат предыдущего запроса с тем же ключом"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес добавленной песни: /songs/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена во внешнем API",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня уже существует или запрос с этим ключом ещё выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConflictResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности использован с другими данными",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось добавить песню",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Внешний API временно недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет песни по списку ID (не более 100) и возвращает результат для каждого ID: deleted, not_found\nили version_mismatch, если для песни в versions указана версия, а песня была изменена.\nЕсли сервис требует If-Match, версия обязательна для каждого ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удалить несколько песен",
+                "parameters": [
+                    {
+                        "description": "ID удаляемых песен и их версии",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteSongsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результаты удаления",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteSongsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный список ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуются версии песен",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось удалить песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Возвращает все данные песни. Поддерживаются условные запросы по ETag (If-None-Match)\nи Last-Modified (If-Modified-Since).\nДля совместимости с прежней версией API при view=lyrics или параметре verse\nвозвращается страница текста песни, как в GET /songs/{id}/lyrics.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "lyrics"
+                        ],
+                        "type": "string",
+                        "description": "lyrics - вернуть страницу текста песни (устарело)",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag сохранённой у клиента версии песни",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия данных песни"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время последнего изменения песни"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Песня не изменилась"
+                    },
+                    "400": {
+                        "description": "Некорректный ID песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет все данные песни: группа и название обязательны, незаданные дата релиза,\nтекст и ссылка очищаются. Для изменения отдельных полей используйте PATCH.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Заменить данные песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Новая информация о песне",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные песни обновлены",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или данные песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня с такими группой и названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConflictResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения ETag",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет песню по ее ID.",
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удалить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня удалена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения ETag",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось удалить песню",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Применяет к песне JSON Merge Patch (RFC 7396): изменяются только переданные поля,\nnull очищает дату релиза, текст или ссылку. Группу и название очистить нельзя.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Изменить отдельные поля песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Изменяемые поля песни",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Изменённая песня",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или данные песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня с такими группой и названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConflictResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения ETag",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый формат тела запроса",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/credits": {
+            "put": {
+                "description": "Заменяет список участников песни: приглашённых исполнителей, композиторов, авторов текста\nи продюсеров. Основной исполнитель песни (group) всегда остаётся участником с ролью primary;\nдополнительных основных исполнителей можно указать с той же ролью. Порядок участников\nс одной ролью сохраняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Задать участников песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Участники песни",
+                        "name": "credits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongCreditsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня с новым списком участников",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные участники или исполнитель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения ETag",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/genres": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Задать жанры песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "ID жанров песни",
+                        "name": "genres",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongGenresRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня с новыми жанрами",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные ID жанров или жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения ETag",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics": {
+            "get": {
+                "description": "Возвращает данные песни и verses_per_page строф её текста, начиная со строфы verse.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить текст песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер первой строфы",
+                        "name": "verse",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 50,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Количество строф на странице",
+                        "name": "verses_per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница текста песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongTextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID песни или номер строфы",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/refresh": {
+            "post": {
+                "description": "Заново запрашивает дату релиза, текст и ссылку во внешнем API и сравнивает их с сохранёнными.\nПо умолчанию полученные данные сохраняются; с dry_run=true возвращаются только изменения,\nа песня не изменяется. Заменяются только пустые поля и поля, полученные из внешнего API:\nзначения, заданные через PUT или PATCH, и пустые значения из внешнего API не применяются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновить данные песни из внешнего API",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только показать изменения, не сохраняя их",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня и изменённые поля",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RefreshSongResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось обновить песню",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Внешний API временно недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/tags": {
+            "put": {
+                "description": "Заменяет теги песни. Теги сравниваются без учёта регистра; отсутствующие теги создаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Задать теги песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Теги песни",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня с новыми тегами",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные теги",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения ETag",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses": {
+            "get": {
+                "description": "Возвращает количество строф и строфы текста песни с их видом (куплет, припев и т.п.),\nопределённым по меткам вида [Chorus] или [Verse 2].",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить строфы песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Строфы песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SongVersesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID песни",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Возвращает теги в алфавитном порядке с количеством песен.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить список тегов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Часть названия тега",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница списка тегов",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TagListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер страницы",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Добавить тег",
+                "parameters": [
+                    {
+                        "description": "Тег",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Тег добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное название тега",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Тег с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Переименовать тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название тега",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Тег переименован",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или название тега",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Тег с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет тег; у песен этот тег снимается.",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Тег удалён",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID тега",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FieldErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег не найден",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.Album": {
+            "description": "Альбом.",
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "artist_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "cover_url": {
+                    "type": "string",
+                    "example": "https://example.com/covers/bhar.jpg"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-03"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Black Holes and Revelations"
+                },
+                "track_count": {
+                    "description": "Количество треков альбома",
+                    "type": "integer",
+                    "example": 11
+                },
+                "tracks": {
+                    "description": "Треки в порядке дисков и номеров; заполняются только для одного альбома",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AlbumTrack"
+                    }
+                }
+            }
+        },
+        "domain.AlbumTrack": {
+            "description": "Трек альбома.",
+            "type": "object",
+            "properties": {
+                "disc_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "song_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "track_number": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "domain.Artist": {
+            "description": "Исполнитель.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "song_count": {
+                    "description": "Количество песен исполнителя в библиотеке",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "domain.Credit": {
+            "description": "Участник песни.",
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string",
+                    "example": "Rihanna"
+                },
+                "artist_id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "role": {
+                    "enum": [
+                        "primary",
+                        "featured",
+                        "composer",
+                        "lyricist",
+                        "producer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CreditRole"
+                        }
+                    ],
+                    "example": "featured"
+                }
+            }
+        },
+        "domain.CreditRole": {
+            "type": "string",
+            "enum": [
+                "primary",
+                "featured",
+                "composer",
+                "lyricist",
+                "producer"
+            ],
+            "x-enum-varnames": [
+                "CreditPrimary",
+                "CreditFeatured",
+                "CreditComposer",
+                "CreditLyricist",
+                "CreditProducer"
+            ]
+        },
+        "domain.EnrichmentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "ready",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "EnrichmentPending",
+                "EnrichmentReady",
+                "EnrichmentFailed"
+            ]
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "link"
+                },
+                "new": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=nHtxh2ZB8zU"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                }
+            }
+        },
+        "domain.Genre": {
+            "description": "Жанр.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alternative Rock"
+                },
+                "parent_id": {
+                    "description": "ID родительского жанра; пуст у жанров верхнего уровня",
+                    "type": "string",
+                    "example": "1"
+                },
+                "song_count": {
+                    "description": "Количество песен с этим жанром (без учёта поджанров)",
+                    "type": "integer",
+                    "example": 12
+                },
+                "subgenres": {
+                    "description": "Поджанры",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Genre"
+                    }
+                }
+            }
+        },
+        "domain.Playlist": {
+            "description": "Плейлист.",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-05-01T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Песни для долгой поездки"
+                },
+                "entries": {
+                    "description": "Записи в порядке воспроизведения; заполняются только для одного плейлиста",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PlaylistEntry"
+                    }
+                },
+                "entry_count": {
+                    "description": "Количество записей в плейлисте",
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "В дорогу"
+                },
+                "owner": {
+                    "description": "Идентификатор пользователя-владельца",
+                    "type": "string",
+                    "example": "user-42"
+                },
+                "public": {
+                    "description": "Виден ли плейлист другим пользователям",
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-05-01T12:00:00Z"
+                }
+            }
+        },
+        "domain.PlaylistEntry": {
+            "description": "Запись плейлиста.",
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string",
+                    "example": "2024-05-01T12:00:00Z"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "position": {
+                    "description": "Позиция записи в плейлисте, начиная с 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "song_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                }
+            }
+        },
+        "domain.Song": {
+            "description": "Модель данных песни.",
+            "type": "object",
+            "properties": {
+                "artist_id": {
+                    "description": "ID исполнителя; его название - в поле group",
+                    "type": "string",
+                    "example": "1"
+                },
+                "credits": {
+                    "description": "Участники песни; первым идёт основной исполнитель",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Credit"
+                    }
+                },
+                "enrichment_error": {
+                    "description": "Последняя ошибка получения данных",
+                    "type": "string",
+                    "example": "music info API returned status 503"
+                },
+                "enrichment_status": {
+                    "description": "Состояние получения данных о песне из внешнего API",
+                    "enum": [
+                        "pending",
+                        "ready",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.EnrichmentStatus"
+                        }
+                    ],
+                    "example": "ready"
+                },
+                "genres": {
+                    "description": "Жанры песни",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SongGenre"
+                    }
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "lyrics": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer? ..."
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "sources": {
+                    "description": "Источник полей, полученных из внешних источников (поле -\u003e имя источника)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "description": "Теги песни",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "live",
+                        "acoustic"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "updated_at": {
+                    "description": "Время последнего изменения данных песни",
+                    "type": "string",
+                    "example": "2024-05-01T12:00:00Z"
+                },
+                "version": {
+                    "description": "Версия данных песни, увеличивается при каждом изменении",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "domain.SongGenre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alternative Rock"
+                }
+            }
+        },
+        "domain.SongListItem": {
+            "description": "Песня в списке результатов.",
+            "type": "object",
+            "properties": {
+                "artist_id": {
+                    "description": "ID исполнителя; его название - в поле group",
+                    "type": "string",
+                    "example": "1"
+                },
+                "credits": {
+                    "description": "Участники песни; первым идёт основной исполнитель",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Credit"
+                    }
+                },
+                "enrichment_error": {
+                    "description": "Последняя ошибка получения данных",
+                    "type": "string",
+                    "example": "music info API returned status 503"
+                },
+                "enrichment_status": {
+                    "description": "Состояние получения данных о песне из внешнего API",
+                    "enum": [
+                        "pending",
+                        "ready",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.EnrichmentStatus"
+                        }
+                    ],
+                    "example": "ready"
+                },
+                "genres": {
+                    "description": "Жанры песни",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SongGenre"
+                    }
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "lyrics": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer? ..."
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6079
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "similarity": {
+                    "type": "number",
+                    "example": 0.7
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "Ooh \u003cmark\u003ebaby\u003c/mark\u003e, don't you know I suffer?"
+                },
+                "sources": {
+                    "description": "Источник полей, полученных из внешних источников (поле -\u003e имя источника)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "description": "Теги песни",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "live",
+                        "acoustic"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "updated_at": {
+                    "description": "Время последнего изменения данных песни",
+                    "type": "string",
+                    "example": "2024-05-01T12:00:00Z"
+                },
+                "version": {
+                    "description": "Версия данных песни, увеличивается при каждом изменении",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "domain.SongSuggestion": {
+            "description": "Вариант исправления названия группы и/или песни.",
+            "type": "object",
+            "properties": {
+                "group_name": {
+                    "type": "string",
+                    "example": "Metallica"
+                },
+                "song_name": {
+                    "type": "string",
+                    "example": "Nothing Else Matters"
+                }
+            }
+        },
+        "domain.Stanza": {
+            "description": "Строфа текста песни.",
+            "type": "object",
+            "properties": {
+                "label": {
+                    "description": "Метка строфы из текста, например \"Verse 2\" для [Verse 2]",
+                    "type": "string",
+                    "example": "Chorus"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Ooh",
+                        "You set my soul alight"
+                    ]
+                },
+                "number": {
+                    "description": "Порядковый номер строфы в тексте, начиная с 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "enum": [
+                        "verse",
+                        "chorus",
+                        "pre_chorus",
+                        "bridge",
+                        "intro",
+                        "outro",
+                        "hook",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.StanzaType"
+                        }
+                    ],
+                    "example": "chorus"
+                }
+            }
+        },
+        "domain.StanzaType": {
+            "type": "string",
+            "enum": [
+                "verse",
+                "chorus",
+                "pre_chorus",
+                "bridge",
+                "intro",
+                "outro",
+                "hook",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "StanzaVerse",
+                "StanzaChorus",
+                "StanzaPreChorus",
+                "StanzaBridge",
+                "StanzaIntro",
+                "StanzaOutro",
+                "StanzaHook",
+                "StanzaOther"
+            ]
+        },
+        "domain.Tag": {
+            "description": "Тег.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "live"
+                },
+                "song_count": {
+                    "description": "Количество песен с этим тегом",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "v1.AddSongRequest": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                }
+            }
+        },
+        "v1.AlbumListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Album"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "v1.AlbumRequest": {
+            "type": "object",
+            "properties": {
+                "artist_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "cover_url": {
+                    "type": "string",
+                    "example": "https://example.com/covers/bhar.jpg"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-03"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Black Holes and Revelations"
+                }
+            }
+        },
+        "v1.AlbumTrackRequest": {
+            "type": "object",
+            "properties": {
+                "disc_number": {
+                    "description": "Номер диска, по умолчанию 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "song_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "track_number": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "v1.AlbumTracksRequest": {
+            "type": "object",
+            "properties": {
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AlbumTrackRequest"
+                    }
+                }
+            }
+        },
+        "v1.ArtistListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Artist"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "v1.ArtistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Muse"
+                }
+            }
+        },
+        "v1.ConflictResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Песня уже существует"
+                }
+            }
+        },
+        "v1.CreditRequest": {
+            "type": "object",
+            "properties": {
+                "artist_id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "primary",
+                        "featured",
+                        "composer",
+                        "lyricist",
+                        "producer"
+                    ],
+                    "example": "featured"
+                }
+            }
+        },
+        "v1.DeleteSongResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "deleted",
+                        "not_found",
+                        "version_mismatch"
+                    ],
+                    "example": "deleted"
+                }
+            }
+        },
+        "v1.DeleteSongsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "versions": {
+                    "description": "Версии песен (ID -\u003e версия из ETag): песня удаляется, только если\nеё версия совпадает. Обязательны для всех ID, если сервис требует If-Match",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "v1.DeleteSongsResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.DeleteSongResult"
+                    }
+                }
+            }
+        },
+        "v1.DuplicatePlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Название копии; по умолчанию - название исходного плейлиста",
+                    "type": "string",
+                    "example": "В дорогу (копия)"
+                }
+            }
+        },
+        "v1.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Описание ошибки"
+                }
+            }
+        },
+        "v1.FieldErrorResponse": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "release_date_from"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Некорректная дата, ожидается формат YYYY-MM-DD"
+                }
+            }
+        },
+        "v1.GenreRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Alternative Rock"
+                },
+                "parent_id": {
+                    "description": "ID родительского жанра; пустой - жанр верхнего уровня",
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "v1.MoveEntryRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "Новая позиция записи, начиная с 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.PlaylistEntryRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "Позиция новой записи, начиная с 1; по умолчанию - в конец плейлиста",
+                    "type": "integer",
+                    "example": 1
+                },
+                "song_id": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "v1.PlaylistListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Playlist"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "v1.PlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Песни для долгой поездки"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "В дорогу"
+                },
+                "public": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "v1.RefreshSongResponse": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "song": {
+                    "$ref": "#/definitions/domain.Song"
+                }
+            }
+        },
+        "v1.SongCreditsRequest": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CreditRequest"
+                    }
+                }
+            }
+        },
+        "v1.SongGenresRequest": {
+            "type": "object",
+            "properties": {
+                "genre_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1",
+                        "2"
+                    ]
+                }
+            }
+        },
+        "v1.SongListResponse": {
+            "type": "object",
+            "properties": {
+                "did_you_mean": {
+                    "$ref": "#/definitions/domain.SongSuggestion"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SongListItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoiLi4uIn0.c2lnbmF0dXJl"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "v1.SongPatchRequest": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "lyrics": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer? ..."
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                }
+            }
+        },
+        "v1.SongTagsRequest": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "live",
+                        "acoustic"
+                    ]
+                }
+            }
+        },
+        "v1.SongTextResponse": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "stanzas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Stanza"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "total_verses": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "verse": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "verses_per_page": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.SongVersesResponse": {
+            "type": "object",
+            "properties": {
+                "song_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "stanzas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Stanza"
+                    }
+                },
+                "total_verses": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "v1.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string",
                     "example": "Сообщение"
                 }
             }
         },
+        "v1.TagListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Tag"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "v1.TagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "live"
+                }
+            }
+        },
         "v1.UpdateSongRequest": {
             "type": "object",
             "properties": {
@@ -377,12 +4055,12 @@ const docTemplate = `{
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
-	Host:             "",
+	Version:          "1.0",
+	Host:             "localhost:8080",
 	BasePath:         "",
 	Schemes:          []string{},
-	Title:            "",
-	Description:      "",
+	Title:            "Online Music Library API",
+	Description:      "This is an API for an online music library, providing functionality to manage and query songs.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -0,0 +1,327 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxAlbumTracks - максимальное количество треков в одном альбоме.
+const maxAlbumTracks = 500
+
+type AlbumListResponse struct {
+	Items      []domain.Album `json:"items"`
+	Page       int            `json:"page" example:"1"`
+	Limit      int            `json:"limit" example:"10"`
+	Total      int            `json:"total" example:"42"`
+	TotalPages int            `json:"total_pages" example:"5"`
+}
+
+type AlbumRequest struct {
+	Title       string `json:"title" example:"Black Holes and Revelations"`
+	ArtistID    string `json:"artist_id" example:"1"`
+	ReleaseDate string `json:"release_date" example:"2006-07-03"`
+	CoverURL    string `json:"cover_url" example:"https://example.com/covers/bhar.jpg"`
+}
+
+type AlbumTrackRequest struct {
+	SongID string `json:"song_id" example:"1"`
+	// Номер диска, по умолчанию 1
+	DiscNumber  int `json:"disc_number" example:"1"`
+	TrackNumber int `json:"track_number" example:"3"`
+}
+
+type AlbumTracksRequest struct {
+	Tracks []AlbumTrackRequest `json:"tracks"`
+}
+
+// GetAlbums возвращает список альбомов.
+// @Summary Получить список альбомов
+// @Description Возвращает альбомы в порядке дат релиза; альбомы без даты - в конце.
+// @Tags albums
+// @Produce  json
+// @Param title query string false "Часть названия альбома"
+// @Param artist_id query int false "ID исполнителя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество записей на странице" default(10) maximum(100)
+// @Success 200 {object} AlbumListResponse "Страница списка альбомов"
+// @Failure 400 {object} FieldErrorResponse "Некорректные параметры запроса"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /albums [get]
+func (h *Handlers) GetAlbums(c echo.Context) error {
+	h.logger.Info("GetAlbums called")
+
+	filter := repository.AlbumFilter{Title: strings.TrimSpace(c.QueryParam("title"))}
+	if value := c.QueryParam("artist_id"); value != "" {
+		id, ok := parseID(value)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, FieldErrorResponse{
+				Message: "ID исполнителя должен быть положительным целым числом", Field: "artist_id",
+			})
+		}
+		filter.ArtistID = id
+	}
+	page, limit, err := pageParams(c)
+	if err != nil {
+		h.logger.Warn("Invalid page param", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	list, err := h.albums.GetAlbums(filter, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get albums", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+	}
+	return c.JSON(http.StatusOK, AlbumListResponse{
+		Items:      list.Items,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		Total:      list.Total,
+		TotalPages: (list.Total + list.Limit - 1) / list.Limit,
+	})
+}
+
+// GetAlbum возвращает альбом с треками.
+// @Summary Получить альбом
+// @Description Возвращает альбом с треками в порядке номеров дисков и треков.
+// @Tags albums
+// @Produce  json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} domain.Album "Альбом"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID альбома"
+// @Failure 404 {object} ErrorResponse "Альбом не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /albums/{id} [get]
+func (h *Handlers) GetAlbum(c echo.Context) error {
+	h.logger.Info("GetAlbum called", slog.String("album_id", c.Param("id")))
+	id, err := albumIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	album, err := h.albums.GetAlbum(id)
+	if err != nil {
+		return h.albumError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, album)
+}
+
+// AddAlbum добавляет альбом.
+// @Summary Добавить альбом
+// @Description Добавляет альбом без треков; треки задаются через PUT /albums/{id}/tracks.
+// @Tags albums
+// @Accept  json
+// @Produce  json
+// @Param album body AlbumRequest true "Альбом"
+// @Success 201 {object} domain.Album "Альбом добавлен"
+// @Header 201 {string} Location "Адрес добавленного альбома: /albums/{id}"
+// @Failure 400 {object} FieldErrorResponse "Некорректные данные альбома или исполнитель не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /albums [post]
+func (h *Handlers) AddAlbum(c echo.Context) error {
+	h.logger.Info("AddAlbum called")
+	in, err := albumInputFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	album, err := h.albums.AddAlbum(in)
+	if err != nil {
+		return h.albumError(c, "", err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, "/albums/"+album.ID)
+	return c.JSON(http.StatusCreated, album)
+}
+
+// UpdateAlbum заменяет данные альбома.
+// @Summary Изменить альбом
+// @Description Заменяет название, исполнителя, дату релиза и обложку альбома; треки не изменяются.
+// @Tags albums
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID альбома"
+// @Param album body AlbumRequest true "Новые данные альбома"
+// @Success 200 {object} domain.Album "Альбом изменён"
+// @Failure 400 {object} FieldErrorResponse "Некорректные данные альбома или исполнитель не найден"
+// @Failure 404 {object} ErrorResponse "Альбом не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /albums/{id} [put]
+func (h *Handlers) UpdateAlbum(c echo.Context) error {
+	h.logger.Info("UpdateAlbum called", slog.String("album_id", c.Param("id")))
+	id, err := albumIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	in, err := albumInputFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	album, err := h.albums.UpdateAlbum(id, in)
+	if err != nil {
+		return h.albumError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, album)
+}
+
+// SetAlbumTracks заменяет треки альбома.
+// @Summary Задать треки альбома
+// @Description Заменяет список треков альбома целиком. Номера треков не обязаны идти подряд,
+// @Description но позиция (диск, номер) и песня в альбоме не должны повторяться.
+// @Tags albums
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID альбома"
+// @Param tracks body AlbumTracksRequest true "Треки альбома"
+// @Success 200 {object} domain.Album "Альбом с новыми треками"
+// @Failure 400 {object} FieldErrorResponse "Некорректные треки или песня не найдена"
+// @Failure 404 {object} ErrorResponse "Альбом не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /albums/{id}/tracks [put]
+func (h *Handlers) SetAlbumTracks(c echo.Context) error {
+	h.logger.Info("SetAlbumTracks called", slog.String("album_id", c.Param("id")))
+	id, err := albumIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	var req AlbumTracksRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные треков"})
+	}
+	tracks, err := albumTracks(req.Tracks)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	album, err := h.albums.SetAlbumTracks(id, tracks)
+	if err != nil {
+		return h.albumError(c, id, err)
+	}
+	h.logger.Info("Album tracks updated", slog.String("album_id", id), slog.Int("tracks", len(tracks)))
+	return c.JSON(http.StatusOK, album)
+}
+
+// DeleteAlbum удаляет альбом.
+// @Summary Удалить альбом
+// @Description Удаляет альбом и его треки; песни остаются в библиотеке.
+// @Tags albums
+// @Param id path int true "ID альбома"
+// @Success 200 {object} SuccessResponse "Альбом удалён"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID альбома"
+// @Failure 404 {object} ErrorResponse "Альбом не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /albums/{id} [delete]
+func (h *Handlers) DeleteAlbum(c echo.Context) error {
+	h.logger.Info("DeleteAlbum called", slog.String("album_id", c.Param("id")))
+	id, err := albumIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	if err := h.albums.DeleteAlbum(id); err != nil {
+		return h.albumError(c, id, err)
+	}
+	h.logger.Info("Album deleted successfully", slog.String("album_id", id))
+	return c.JSON(http.StatusOK, SuccessResponse{"Альбом удалён"})
+}
+
+// albumInputFromBody разбирает и проверяет данные альбома из тела запроса.
+func albumInputFromBody(c echo.Context) (repository.AlbumInput, error) {
+	var req AlbumRequest
+	if err := c.Bind(&req); err != nil {
+		return repository.AlbumInput{}, &paramError{"", "Некорректные данные альбома"}
+	}
+	in := repository.AlbumInput{
+		Title:       strings.TrimSpace(req.Title),
+		ReleaseDate: strings.TrimSpace(req.ReleaseDate),
+		CoverURL:    strings.TrimSpace(req.CoverURL),
+	}
+	if in.Title == "" {
+		return in, &paramError{"title", "Поле обязательно"}
+	}
+	if utf8.RuneCountInString(in.Title) > maxSongNameLen {
+		return in, &paramError{"title", fmt.Sprintf("Не более %d символов", maxSongNameLen)}
+	}
+	id, ok := parseID(strings.TrimSpace(req.ArtistID))
+	if !ok {
+		return in, &paramError{"artist_id", "ID исполнителя должен быть положительным целым числом"}
+	}
+	in.ArtistID = id
+	if in.ReleaseDate != "" {
+		if _, err := time.Parse(repository.DateLayout, in.ReleaseDate); err != nil {
+			return in, &paramError{"release_date", "Некорректная дата, ожидается формат YYYY-MM-DD"}
+		}
+	}
+	if in.CoverURL != "" {
+		if utf8.RuneCountInString(in.CoverURL) > maxSongLinkLen {
+			return in, &paramError{"cover_url", fmt.Sprintf("Не более %d символов", maxSongLinkLen)}
+		}
+		u, err := url.Parse(in.CoverURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return in, &paramError{"cover_url", "Ожидается адрес http или https"}
+		}
+	}
+	return in, nil
+}
+
+// albumTracks проверяет треки альбома. Номер диска по умолчанию - 1.
+func albumTracks(req []AlbumTrackRequest) ([]domain.AlbumTrack, error) {
+	if len(req) > maxAlbumTracks {
+		return nil, &paramError{"tracks", fmt.Sprintf("Не более %d треков", maxAlbumTracks)}
+	}
+	type position struct{ disc, track int }
+	songs := make(map[string]bool, len(req))
+	positions := make(map[position]bool, len(req))
+	tracks := make([]domain.AlbumTrack, len(req))
+	for i, t := range req {
+		field := fmt.Sprintf("tracks[%d]", i)
+		id, ok := parseID(strings.TrimSpace(t.SongID))
+		if !ok {
+			return nil, &paramError{field + ".song_id", "ID песни должен быть положительным целым числом"}
+		}
+		if songs[id] {
+			return nil, &paramError{field + ".song_id", "Песня уже есть в альбоме"}
+		}
+		if t.DiscNumber == 0 {
+			t.DiscNumber = 1
+		}
+		if t.DiscNumber < 0 {
+			return nil, &paramError{field + ".disc_number", "Номер диска должен быть положительным"}
+		}
+		if t.TrackNumber < 1 {
+			return nil, &paramError{field + ".track_number", "Номер трека должен быть положительным"}
+		}
+		pos := position{t.DiscNumber, t.TrackNumber}
+		if positions[pos] {
+			return nil, &paramError{field + ".track_number", "Позиция уже занята другим треком"}
+		}
+		songs[id], positions[pos] = true, true
+		tracks[i] = domain.AlbumTrack{DiscNumber: t.DiscNumber, TrackNumber: t.TrackNumber, SongID: id}
+	}
+	return tracks, nil
+}
+
+// albumError формирует ответ на ошибку работы с альбомом.
+func (h *Handlers) albumError(c echo.Context, id string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAlbumNotFound):
+		h.logger.Warn("Album not found", slog.String("album_id", id))
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Альбом не найден"})
+	case errors.Is(err, repository.ErrArtistNotFound):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Исполнитель не найден", Field: "artist_id"})
+	case errors.Is(err, repository.ErrTrackSongNotFound):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Песня не найдена", Field: "tracks"})
+	case errors.Is(err, repository.ErrDuplicateTrack):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Треки альбома повторяются", Field: "tracks"})
+	}
+	h.logger.Error("Album request failed", slog.String("album_id", id), slog.Any("error", err))
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+}
@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+type ArtistListResponse struct {
+	Items      []domain.Artist `json:"items"`
+	Page       int             `json:"page" example:"1"`
+	Limit      int             `json:"limit" example:"10"`
+	Total      int             `json:"total" example:"42"`
+	TotalPages int             `json:"total_pages" example:"5"`
+}
+
+type ArtistRequest struct {
+	Name string `json:"name" example:"Muse"`
+}
+
+// GetArtists возвращает список исполнителей.
+// @Summary Получить список исполнителей
+// @Description Возвращает исполнителей в алфавитном порядке с количеством их песен.
+// @Tags artists
+// @Produce  json
+// @Param name query string false "Часть названия исполнителя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество записей на странице" default(10) maximum(100)
+// @Success 200 {object} ArtistListResponse "Страница списка исполнителей"
+// @Failure 400 {object} FieldErrorResponse "Некорректный номер страницы"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /artists [get]
+func (h *Handlers) GetArtists(c echo.Context) error {
+	h.logger.Info("GetArtists called")
+
+	page, limit, err := pageParams(c)
+	if err != nil {
+		h.logger.Warn("Invalid page param", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	list, err := h.artists.GetArtists(strings.TrimSpace(c.QueryParam("name")), page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get artists", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+	}
+	return c.JSON(http.StatusOK, ArtistListResponse{
+		Items:      list.Items,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		Total:      list.Total,
+		TotalPages: (list.Total + list.Limit - 1) / list.Limit,
+	})
+}
+
+// GetArtist возвращает исполнителя по ID.
+// @Summary Получить исполнителя
+// @Tags artists
+// @Produce  json
+// @Param id path int true "ID исполнителя"
+// @Success 200 {object} domain.Artist "Исполнитель"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID исполнителя"
+// @Failure 404 {object} ErrorResponse "Исполнитель не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /artists/{id} [get]
+func (h *Handlers) GetArtist(c echo.Context) error {
+	h.logger.Info("GetArtist called", slog.String("artist_id", c.Param("id")))
+	id, err := artistIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	artist, err := h.artists.GetArtist(id)
+	if err != nil {
+		return h.artistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, artist)
+}
+
+// AddArtist добавляет исполнителя.
+// @Summary Добавить исполнителя
+// @Tags artists
+// @Accept  json
+// @Produce  json
+// @Param artist body ArtistRequest true "Исполнитель"
+// @Success 201 {object} domain.Artist "Исполнитель добавлен"
+// @Header 201 {string} Location "Адрес добавленного исполнителя: /artists/{id}"
+// @Failure 400 {object} FieldErrorResponse "Некорректное название исполнителя"
+// @Failure 409 {object} ConflictResponse "Исполнитель с таким названием уже существует"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /artists [post]
+func (h *Handlers) AddArtist(c echo.Context) error {
+	h.logger.Info("AddArtist called")
+	name, err := artistNameFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	artist, err := h.artists.AddArtist(name)
+	if err != nil {
+		return h.artistError(c, "", err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, "/artists/"+artist.ID)
+	return c.JSON(http.StatusCreated, artist)
+}
+
+// RenameArtist переименовывает исполнителя.
+// @Summary Переименовать исполнителя
+// @Description Изменяет название исполнителя; название группы меняется во всех его песнях.
+// @Tags artists
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID исполнителя"
+// @Param artist body ArtistRequest true "Новое название исполнителя"
+// @Success 200 {object} domain.Artist "Исполнитель переименован"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID или название исполнителя"
+// @Failure 404 {object} ErrorResponse "Исполнитель не найден"
+// @Failure 409 {object} ConflictResponse "Исполнитель с таким названием уже существует"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /artists/{id} [put]
+func (h *Handlers) RenameArtist(c echo.Context) error {
+	h.logger.Info("RenameArtist called", slog.String("artist_id", c.Param("id")))
+	id, err := artistIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	name, err := artistNameFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	artist, err := h.artists.RenameArtist(id, name)
+	if err != nil {
+		return h.artistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, artist)
+}
+
+// DeleteArtist удаляет исполнителя.
+// @Summary Удалить исполнителя
+// @Description Удаляет исполнителя, у которого нет песен и альбомов.
+// @Tags artists
+// @Param id path int true "ID исполнителя"
+// @Success 200 {object} SuccessResponse "Исполнитель удалён"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID исполнителя"
+// @Failure 404 {object} ErrorResponse "Исполнитель не найден"
+// @Failure 409 {object} ErrorResponse "У исполнителя есть песни или альбомы"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /artists/{id} [delete]
+func (h *Handlers) DeleteArtist(c echo.Context) error {
+	h.logger.Info("DeleteArtist called", slog.String("artist_id", c.Param("id")))
+	id, err := artistIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	if err := h.artists.DeleteArtist(id); err != nil {
+		return h.artistError(c, id, err)
+	}
+	h.logger.Info("Artist deleted successfully", slog.String("artist_id", id))
+	return c.JSON(http.StatusOK, SuccessResponse{"Исполнитель удалён"})
+}
+
+// GetArtistSongs возвращает песни исполнителя.
+// @Summary Получить песни исполнителя
+// @Description Возвращает песни исполнителя с теми же фильтрами, сортировкой и пагинацией, что и GET /songs.
+// @Tags artists
+// @Produce  json
+// @Param id path int true "ID исполнителя"
+// @Param q query string false "Полнотекстовый поиск по названию и тексту песни"
+// @Param song_name query string false "Название песни"
+// @Param sort query string false "Сортировка, как в GET /songs"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество записей на странице" default(10) maximum(100)
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {object} SongListResponse "Страница списка песен исполнителя"
+// @Failure 400 {object} FieldErrorResponse "Некорректные параметры запроса"
+// @Failure 404 {object} ErrorResponse "Исполнитель не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /artists/{id}/songs [get]
+func (h *Handlers) GetArtistSongs(c echo.Context) error {
+	h.logger.Info("GetArtistSongs called", slog.String("artist_id", c.Param("id")))
+	id, err := artistIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	if _, err := h.artists.GetArtist(id); err != nil {
+		return h.artistError(c, id, err)
+	}
+
+	filter, err := songFilterFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	filter.ArtistID = id
+	return h.listSongs(c, filter)
+}
+
+// artistNameFromBody возвращает название исполнителя из тела запроса.
+func artistNameFromBody(c echo.Context) (string, error) {
+	var req ArtistRequest
+	if err := c.Bind(&req); err != nil {
+		return "", &paramError{"", "Некорректные данные исполнителя"}
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", &paramError{"name", "Поле обязательно"}
+	}
+	if utf8.RuneCountInString(name) > maxSongNameLen {
+		return "", &paramError{"name", "Слишком длинное название"}
+	}
+	return name, nil
+}
+
+// artistError формирует ответ на ошибку работы с исполнителем.
+func (h *Handlers) artistError(c echo.Context, id string, err error) error {
+	var exists *repository.ArtistExistsError
+	switch {
+	case errors.As(err, &exists):
+		return c.JSON(http.StatusConflict, ConflictResponse{Message: "Исполнитель с таким названием уже существует", ID: exists.ExistingID})
+	case errors.Is(err, repository.ErrArtistNotFound):
+		h.logger.Warn("Artist not found", slog.String("artist_id", id))
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Исполнитель не найден"})
+	case errors.Is(err, repository.ErrArtistHasSongs):
+		return c.JSON(http.StatusConflict, ErrorResponse{"У исполнителя есть песни"})
+	case errors.Is(err, repository.ErrArtistHasAlbums):
+		return c.JSON(http.StatusConflict, ErrorResponse{"У исполнителя есть альбомы"})
+	}
+	h.logger.Error("Artist request failed", slog.String("artist_id", id), slog.Any("error", err))
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+}
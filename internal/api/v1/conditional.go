@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errPreconditionRequired = errors.New("If-Match header is required")
+
+// songETag возвращает ETag песни: её версию в кавычках.
+func songETag(song *domain.Song) string {
+	return `"` + strconv.FormatInt(song.Version, 10) + `"`
+}
+
+// ifMatchVersions возвращает версии песни из заголовка If-Match, при которых
+// разрешено её изменение, или nil, если версия не проверяется (заголовка нет
+// или указан "*"). Слабые ETag (W/"...") при сравнении с If-Match не совпадают
+// ни с одной версией. Если заголовка нет, а required = true, возвращает
+// errPreconditionRequired.
+func ifMatchVersions(c echo.Context, required bool) ([]int64, error) {
+	header := c.Request().Header.Get("If-Match")
+	if header == "" {
+		if required {
+			return nil, errPreconditionRequired
+		}
+		return nil, nil
+	}
+	versions := []int64{}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return nil, nil
+		}
+		unquoted, err := strconv.Unquote(candidate)
+		if err != nil || !strings.HasPrefix(candidate, `"`) {
+			continue
+		}
+		if version, err := strconv.ParseInt(unquoted, 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+// preconditionError формирует ответ на несоблюдение условия If-Match.
+// Возвращает false, если err не связана с условием.
+func preconditionError(c echo.Context, err error) (bool, error) {
+	switch {
+	case errors.Is(err, errPreconditionRequired):
+		return true, c.JSON(http.StatusPreconditionRequired, ErrorResponse{"Требуется заголовок If-Match с ETag песни"})
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return true, c.JSON(http.StatusPreconditionFailed, ErrorResponse{"Песня была изменена, получите её заново"})
+	}
+	return false, nil
+}
+
+// setValidators добавляет в ответ заголовки ETag и Last-Modified.
+func setValidators(c echo.Context, etag string, modified time.Time) {
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	if !modified.IsZero() {
+		header.Set(echo.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified сообщает, совпадает ли представление у клиента с текущим
+// по заголовкам If-None-Match или, если его нет, If-Modified-Since.
+func notModified(c echo.Context, etag string, modified time.Time) bool {
+	req := c.Request()
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag, true)
+	}
+	if ims := req.Header.Get(echo.HeaderIfModifiedSince); ims != "" && !modified.IsZero() {
+		since, err := http.ParseTime(ims)
+		// Last-Modified передаётся с точностью до секунды
+		return err == nil && !modified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagMatches сообщает, есть ли etag в списке из заголовка If-None-Match / If-Match.
+// weak = true разрешает слабое сравнение (W/"x" совпадает с "x").
+func etagMatches(list, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
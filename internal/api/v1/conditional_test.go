@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// conditionalContext возвращает контекст запроса с заданными заголовками.
+func conditionalContext(header map[string]string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/songs/1", nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		required bool
+		want     []int64
+		wantErr  error
+	}{
+		{name: "no header", want: nil},
+		{name: "no header when required", required: true, wantErr: errPreconditionRequired},
+		{name: "version", header: `"3"`, required: true, want: []int64{3}},
+		{name: "list", header: `"3", "5" ,"8"`, want: []int64{3, 5, 8}},
+		{name: "any version", header: `*`, required: true, want: nil},
+		{name: "any version in list", header: `"3", *`, want: nil},
+		{name: "weak etag never matches", header: `W/"3"`, want: []int64{}},
+		{name: "weak and strong", header: `W/"3", "4"`, want: []int64{4}},
+		{name: "unquoted", header: `3`, want: []int64{}},
+		{name: "not a number", header: `"abc"`, want: []int64{}},
+		{name: "unterminated quote", header: `"3`, want: []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := map[string]string{}
+			if tt.header != "" {
+				header["If-Match"] = tt.header
+			}
+			got, err := ifMatchVersions(conditionalContext(header), tt.required)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ifMatchVersions(%q) error = %v, want %v", tt.header, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ifMatchVersions(%q) = %#v, want %#v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		weak bool
+		want bool
+	}{
+		{"same", `"3"`, false, true},
+		{"other", `"4"`, false, false},
+		{"any", `*`, false, true},
+		{"in list", `"1", "3"`, false, true},
+		{"not in list", `"1", "2"`, false, false},
+		{"weak with strong comparison", `W/"3"`, false, false},
+		{"weak with weak comparison", `W/"3"`, true, true},
+		{"weak list with weak comparison", `W/"1",W/"3"`, true, true},
+		{"unquoted", `3`, true, false},
+		{"empty", ``, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.list, `"3"`, tt.weak); got != tt.want {
+				t.Errorf("etagMatches(%q, weak=%v) = %v, want %v", tt.list, tt.weak, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 12, 0, 0, 500_000_000, time.UTC)
+	tests := []struct {
+		name     string
+		header   map[string]string
+		modified time.Time
+		want     bool
+	}{
+		{name: "no headers", modified: modified, want: false},
+		{name: "same etag", header: map[string]string{"If-None-Match": `"3"`}, modified: modified, want: true},
+		{name: "weak etag", header: map[string]string{"If-None-Match": `W/"3"`}, modified: modified, want: true},
+		{name: "other etag", header: map[string]string{"If-None-Match": `"2"`}, modified: modified, want: false},
+		{name: "any etag", header: map[string]string{"If-None-Match": `*`}, modified: modified, want: true},
+		{
+			name: "etag takes precedence over date",
+			header: map[string]string{
+				"If-None-Match":     `"2"`,
+				"If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat),
+			},
+			modified: modified,
+			want:     false,
+		},
+		{name: "not modified since", header: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, modified: modified, want: true},
+		{name: "modified since", header: map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)}, modified: modified, want: false},
+		{name: "malformed date", header: map[string]string{"If-Modified-Since": "yesterday"}, modified: modified, want: false},
+		{name: "unknown modification time", header: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notModified(conditionalContext(tt.header), `"3"`, tt.modified); got != tt.want {
+				t.Errorf("notModified(%v) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"strings"
+)
+
+// maxSongCredits - максимальное количество участников одной песни.
+const maxSongCredits = 50
+
+type CreditRequest struct {
+	ArtistID string `json:"artist_id" example:"2"`
+	Role     string `json:"role" example:"featured" enums:"primary,featured,composer,lyricist,producer"`
+}
+
+type SongCreditsRequest struct {
+	Credits []CreditRequest `json:"credits"`
+}
+
+// SetSongCredits заменяет участников песни.
+// @Summary Задать участников песни
+// @Description Заменяет список участников песни: приглашённых исполнителей, композиторов, авторов текста
+// @Description и продюсеров. Основной исполнитель песни (group) всегда остаётся участником с ролью primary;
+// @Description дополнительных основных исполнителей можно указать с той же ролью. Порядок участников
+// @Description с одной ролью сохраняется.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни из GET /songs/{id}"
+// @Param credits body SongCreditsRequest true "Участники песни"
+// @Success 200 {object} domain.Song "Песня с новым списком участников"
+// @Header 200 {string} ETag "Версия песни"
+// @Failure 400 {object} FieldErrorResponse "Некорректные участники или исполнитель не найден"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 412 {object} ErrorResponse "Песня изменена после получения ETag"
+// @Failure 428 {object} ErrorResponse "Требуется заголовок If-Match"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /songs/{id}/credits [put]
+func (h *Handlers) SetSongCredits(c echo.Context) error {
+	h.logger.Info("SetSongCredits called", slog.String("song_id", c.Param("id")))
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	versions, err := ifMatchVersions(c, h.cfg.HTTPServer.RequireIfMatch)
+	if handled, resp := preconditionError(c, err); handled {
+		return resp
+	}
+
+	var req SongCreditsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные участников"})
+	}
+	credits, err := songCredits(req.Credits)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	song, err := h.service.SetSongCredits(id, credits, versions)
+	if errors.Is(err, repository.ErrArtistNotFound) {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Исполнитель не найден", Field: "credits"})
+	}
+	if err != nil {
+		return h.updateSongError(c, id, err)
+	}
+	h.logger.Info("Song credits updated", slog.String("song_id", id), slog.Int("credits", len(credits)))
+	c.Response().Header().Set("ETag", songETag(song))
+	return c.JSON(http.StatusOK, song)
+}
+
+// songCredits проверяет участников песни: исполнитель не может повторяться
+// в одной роли.
+func songCredits(req []CreditRequest) ([]domain.Credit, error) {
+	if len(req) > maxSongCredits {
+		return nil, &paramError{"credits", fmt.Sprintf("Не более %d участников", maxSongCredits)}
+	}
+	seen := make(map[domain.Credit]bool, len(req))
+	credits := make([]domain.Credit, len(req))
+	for i, cr := range req {
+		field := fmt.Sprintf("credits[%d]", i)
+		id, ok := parseID(strings.TrimSpace(cr.ArtistID))
+		if !ok {
+			return nil, &paramError{field + ".artist_id", "ID исполнителя должен быть положительным целым числом"}
+		}
+		role := domain.CreditRole(strings.ToLower(strings.TrimSpace(cr.Role)))
+		if !role.Valid() {
+			return nil, &paramError{field + ".role", "Допустимые значения: primary, featured, composer, lyricist, producer"}
+		}
+		credit := domain.Credit{ArtistID: id, Role: role}
+		if seen[credit] {
+			return nil, &paramError{field, "Исполнитель уже указан с этой ролью"}
+		}
+		seen[credit] = true
+		credits[i] = credit
+	}
+	return credits, nil
+}
@@ -0,0 +1,261 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSongGenres - максимальное количество жанров одной песни.
+const maxSongGenres = 20
+
+type GenreRequest struct {
+	Name string `json:"name" example:"Alternative Rock"`
+	// ID родительского жанра; пустой - жанр верхнего уровня
+	ParentID string `json:"parent_id" example:"1"`
+}
+
+type SongGenresRequest struct {
+	GenreIDs []string `json:"genre_ids" example:"1,2"`
+}
+
+// GetGenres возвращает дерево жанров.
+// @Summary Получить жанры
+// @Description Возвращает жанры верхнего уровня с вложенными поджанрами в алфавитном порядке.
+// @Tags genres
+// @Produce  json
+// @Success 200 {array} domain.Genre "Дерево жанров"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /genres [get]
+func (h *Handlers) GetGenres(c echo.Context) error {
+	h.logger.Info("GetGenres called")
+	genres, err := h.genres.GetGenres()
+	if err != nil {
+		h.logger.Error("Failed to get genres", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+	}
+	return c.JSON(http.StatusOK, genres)
+}
+
+// GetGenre возвращает жанр с поджанрами.
+// @Summary Получить жанр
+// @Tags genres
+// @Produce  json
+// @Param id path int true "ID жанра"
+// @Success 200 {object} domain.Genre "Жанр с поджанрами"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID жанра"
+// @Failure 404 {object} ErrorResponse "Жанр не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /genres/{id} [get]
+func (h *Handlers) GetGenre(c echo.Context) error {
+	h.logger.Info("GetGenre called", slog.String("genre_id", c.Param("id")))
+	id, err := genreIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	genre, err := h.genres.GetGenre(id)
+	if err != nil {
+		return h.genreError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, genre)
+}
+
+// AddGenre добавляет жанр.
+// @Summary Добавить жанр
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param genre body GenreRequest true "Жанр"
+// @Success 201 {object} domain.Genre "Жанр добавлен"
+// @Header 201 {string} Location "Адрес добавленного жанра: /genres/{id}"
+// @Failure 400 {object} FieldErrorResponse "Некорректные данные жанра или родительский жанр не найден"
+// @Failure 409 {object} ErrorResponse "Жанр с таким названием уже существует"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /genres [post]
+func (h *Handlers) AddGenre(c echo.Context) error {
+	h.logger.Info("AddGenre called")
+	req, err := genreFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	genre, err := h.genres.AddGenre(req.Name, req.ParentID)
+	if err != nil {
+		return h.genreError(c, "", err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, "/genres/"+genre.ID)
+	return c.JSON(http.StatusCreated, genre)
+}
+
+// UpdateGenre изменяет жанр.
+// @Summary Изменить жанр
+// @Description Изменяет название жанра и переносит его в другой родительский жанр
+// @Description (пустой parent_id - на верхний уровень) вместе с поджанрами.
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID жанра"
+// @Param genre body GenreRequest true "Новые данные жанра"
+// @Success 200 {object} domain.Genre "Жанр изменён"
+// @Failure 400 {object} FieldErrorResponse "Некорректные данные жанра, родительский жанр не найден или является поджанром"
+// @Failure 404 {object} ErrorResponse "Жанр не найден"
+// @Failure 409 {object} ErrorResponse "Жанр с таким названием уже существует"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /genres/{id} [put]
+func (h *Handlers) UpdateGenre(c echo.Context) error {
+	h.logger.Info("UpdateGenre called", slog.String("genre_id", c.Param("id")))
+	id, err := genreIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	req, err := genreFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	genre, err := h.genres.UpdateGenre(id, req.Name, req.ParentID)
+	if err != nil {
+		return h.genreError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, genre)
+}
+
+// DeleteGenre удаляет жанр.
+// @Summary Удалить жанр
+// @Description Удаляет жанр без поджанров; у песен этот жанр снимается.
+// @Tags genres
+// @Param id path int true "ID жанра"
+// @Success 200 {object} SuccessResponse "Жанр удалён"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID жанра"
+// @Failure 404 {object} ErrorResponse "Жанр не найден"
+// @Failure 409 {object} ErrorResponse "У жанра есть поджанры"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /genres/{id} [delete]
+func (h *Handlers) DeleteGenre(c echo.Context) error {
+	h.logger.Info("DeleteGenre called", slog.String("genre_id", c.Param("id")))
+	id, err := genreIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	if err := h.genres.DeleteGenre(id); err != nil {
+		return h.genreError(c, id, err)
+	}
+	h.logger.Info("Genre deleted successfully", slog.String("genre_id", id))
+	return c.JSON(http.StatusOK, SuccessResponse{"Жанр удалён"})
+}
+
+// SetSongGenres заменяет жанры песни.
+// @Summary Задать жанры песни
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни из GET /songs/{id}"
+// @Param genres body SongGenresRequest true "ID жанров песни"
+// @Success 200 {object} domain.Song "Песня с новыми жанрами"
+// @Header 200 {string} ETag "Версия песни"
+// @Failure 400 {object} FieldErrorResponse "Некорректные ID жанров или жанр не найден"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 412 {object} ErrorResponse "Песня изменена после получения ETag"
+// @Failure 428 {object} ErrorResponse "Требуется заголовок If-Match"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /songs/{id}/genres [put]
+func (h *Handlers) SetSongGenres(c echo.Context) error {
+	h.logger.Info("SetSongGenres called", slog.String("song_id", c.Param("id")))
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	versions, err := ifMatchVersions(c, h.cfg.HTTPServer.RequireIfMatch)
+	if handled, resp := preconditionError(c, err); handled {
+		return resp
+	}
+
+	var req SongGenresRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные жанров"})
+	}
+	if len(req.GenreIDs) > maxSongGenres {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{
+			Message: fmt.Sprintf("Не более %d жанров", maxSongGenres), Field: "genre_ids",
+		})
+	}
+	var genreIDs []string
+	seen := map[string]bool{}
+	for i, value := range req.GenreIDs {
+		genreID, ok := parseID(strings.TrimSpace(value))
+		if !ok {
+			return c.JSON(http.StatusBadRequest, FieldErrorResponse{
+				Message: "ID жанра должен быть положительным целым числом", Field: fmt.Sprintf("genre_ids[%d]", i),
+			})
+		}
+		if !seen[genreID] {
+			seen[genreID] = true
+			genreIDs = append(genreIDs, genreID)
+		}
+	}
+
+	song, err := h.service.SetSongGenres(id, genreIDs, versions)
+	if errors.Is(err, repository.ErrGenreNotFound) {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Жанр не найден", Field: "genre_ids"})
+	}
+	if err != nil {
+		return h.updateSongError(c, id, err)
+	}
+	c.Response().Header().Set("ETag", songETag(song))
+	return c.JSON(http.StatusOK, song)
+}
+
+// genreFromBody разбирает и проверяет данные жанра из тела запроса.
+func genreFromBody(c echo.Context) (GenreRequest, error) {
+	var req GenreRequest
+	if err := c.Bind(&req); err != nil {
+		return req, &paramError{"", "Некорректные данные жанра"}
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return req, &paramError{"name", "Поле обязательно"}
+	}
+	if utf8.RuneCountInString(req.Name) > maxSongNameLen {
+		return req, &paramError{"name", fmt.Sprintf("Не более %d символов", maxSongNameLen)}
+	}
+	if strings.Contains(req.Name, ",") {
+		return req, &paramError{"name", "Название не может содержать запятую"}
+	}
+	if req.ParentID = strings.TrimSpace(req.ParentID); req.ParentID != "" {
+		id, ok := parseID(req.ParentID)
+		if !ok {
+			return req, &paramError{"parent_id", "ID жанра должен быть положительным целым числом"}
+		}
+		req.ParentID = id
+	}
+	return req, nil
+}
+
+// genreError формирует ответ на ошибку работы с жанром.
+func (h *Handlers) genreError(c echo.Context, id string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrGenreNotFound):
+		h.logger.Warn("Genre not found", slog.String("genre_id", id))
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Жанр не найден"})
+	case errors.Is(err, repository.ErrParentNotFound):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Родительский жанр не найден", Field: "parent_id"})
+	case errors.Is(err, repository.ErrGenreCycle):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{
+			Message: "Жанр нельзя сделать поджанром самого себя или своего поджанра", Field: "parent_id",
+		})
+	case errors.Is(err, repository.ErrGenreExists):
+		return c.JSON(http.StatusConflict, ErrorResponse{"Жанр с таким названием уже существует"})
+	case errors.Is(err, repository.ErrGenreHasSubgenres):
+		return c.JSON(http.StatusConflict, ErrorResponse{"У жанра есть поджанры"})
+	}
+	h.logger.Error("Genre request failed", slog.String("genre_id", id), slog.Any("error", err))
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+}
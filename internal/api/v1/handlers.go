@@ -4,134 +4,383 @@ import (
 	"errors"
 	"github.com/labstack/echo/v4"
 	"log/slog"
+	"math"
+	"mime"
 	"music-test-lib/config"
 	"music-test-lib/internal/domain"
 	_ "music-test-lib/internal/domain"
+	"music-test-lib/internal/musicinfo"
 	"music-test-lib/internal/repository"
 	"music-test-lib/internal/service"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
-// Handlers содержит методы-обработчики для работы с песнями.
+// Handlers содержит методы-обработчики для работы с песнями и исполнителями.
 type Handlers struct {
-	logger  *slog.Logger
-	service *service.SongService
-	cfg     *config.Config
+	logger    *slog.Logger
+	service   *service.SongService
+	artists   *service.ArtistService
+	albums    *service.AlbumService
+	genres    *service.GenreService
+	tags      *service.TagService
+	playlists *service.PlaylistService
+	cfg       *config.Config
+}
+
+// Services - сервисы, используемые обработчиками.
+type Services struct {
+	Songs     *service.SongService
+	Artists   *service.ArtistService
+	Albums    *service.AlbumService
+	Genres    *service.GenreService
+	Tags      *service.TagService
+	Playlists *service.PlaylistService
 }
 
 // NewHandlers создаёт новый экземпляр Handlers с переданным логгером.
-func NewHandlers(logger *slog.Logger, service *service.SongService, cfg *config.Config) *Handlers {
-	return &Handlers{logger: logger, service: service, cfg: cfg}
+func NewHandlers(logger *slog.Logger, services Services, cfg *config.Config) *Handlers {
+	return &Handlers{
+		logger:    logger,
+		service:   services.Songs,
+		artists:   services.Artists,
+		albums:    services.Albums,
+		genres:    services.Genres,
+		tags:      services.Tags,
+		playlists: services.Playlists,
+		cfg:       cfg,
+	}
 }
 
 type ErrorResponse struct {
 	Message string `json:"message" example:"Описание ошибки"`
 }
+
+// FieldErrorResponse - ошибка в значении конкретного параметра запроса.
+type FieldErrorResponse struct {
+	Message string `json:"message" example:"Некорректная дата, ожидается формат YYYY-MM-DD"`
+	Field   string `json:"field,omitempty" example:"release_date_from"`
+}
+
+// ConflictResponse - конфликт с текущим состоянием библиотеки.
+// ID содержит идентификатор уже существующей песни, если конфликт вызван дубликатом.
+type ConflictResponse struct {
+	Message string `json:"message" example:"Песня уже существует"`
+	ID      string `json:"id,omitempty" example:"1"`
+}
+
 type SuccessResponse struct {
 	Message string `json:"message" example:"Сообщение"`
 }
 
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// SongListResponse - страница списка песен.
+type SongListResponse struct {
+	Items      []domain.SongListItem  `json:"items"`
+	Page       int                    `json:"page,omitempty" example:"1"`
+	Limit      int                    `json:"limit" example:"10"`
+	Total      int                    `json:"total" example:"42"`
+	TotalPages int                    `json:"total_pages" example:"5"`
+	NextCursor string                 `json:"next_cursor,omitempty" example:"eyJzIjoiLi4uIn0.c2lnbmF0dXJl"`
+	DidYouMean *domain.SongSuggestion `json:"did_you_mean,omitempty"`
+}
+
 // GetSongs возвращает список песен с фильтрацией по всем полям и пагинацией.
 // @Summary Получить список песен
-// @Description Возвращает список песен с возможностью фильтрации по всем полям (группа, название, дата выпуска, текст) и пагинацией.
+// @Description Возвращает список песен с возможностью фильтрации по всем полям (группа, название, дата выпуска, текст), сортировки и пагинацией.
+// @Description Без параметра sort песни упорядочиваются по id, а при полнотекстовом поиске (q) - по убыванию релевантности.
+// @Description Сортировка по relevance доступна только вместе с q. Песни без даты релиза при сортировке по release_date считаются самыми поздними.
+// @Description При поиске по q каждая песня содержит релевантность (rank) и фрагменты текста с выделенными совпадениями (snippet).
+// @Description При fuzzy=true group_name и song_name сравниваются по сходству триграмм, песни содержат оценку сходства (similarity) и по умолчанию упорядочиваются по ней.
+// @Description Если точных совпадений по group_name/song_name нет, в did_you_mean возвращаются похожие названия из библиотеки.
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Param q query string false "Полнотекстовый поиск по группе, названию и тексту песни (синтаксис websearch_to_tsquery: слова, фразы в кавычках, or, -исключение)"
 // @Param group_name query string false "Название группы"
+// @Param group_name_match query string false "Режим сравнения названия группы" Enums(contains, prefix, exact)
 // @Param song_name query string false "Название песни"
+// @Param song_name_match query string false "Режим сравнения названия песни" Enums(contains, prefix, exact)
 // @Param release_date query string false "Дата релиза (формат: YYYY-MM-DD)"
+// @Param release_date_from query string false "Дата релиза не раньше (формат: YYYY-MM-DD)"
+// @Param release_date_to query string false "Дата релиза не позже (формат: YYYY-MM-DD)"
+// @Param release_year query int false "Год релиза" example(1997)
+// @Param decade query string false "Десятилетие релиза" example(1990s)
+// @Param artist_id query int false "ID исполнителя"
+// @Param album_id query int false "ID альбома"
+// @Param credited_artist_id query int false "ID исполнителя, участвующего в песне (в любой роли или в роли credit_role)"
+// @Param credit_role query string false "Роль участника песни" Enums(primary, featured, composer, lyricist, producer)
+// @Param genre query []string false "Жанры (ID или названия): песня подходит, если у неё есть любой из них" collectionFormat(multi)
+// @Param include_subgenres query bool false "Учитывать поджанры жанров из genre" default(false)
+// @Param tag query []string false "Теги" collectionFormat(multi)
+// @Param tag_mode query string false "Песня должна иметь любой из тегов (any) или все теги (all)" Enums(any, all) default(any)
+// @Param fuzzy query bool false "Нечёткий поиск по group_name и song_name с учётом опечаток" default(false)
 // @Param lyrics query string false "Часть текста песни"
-// @Param page query int false "Номер страницы"
-// @Param limit query int false "Количество записей на странице"
-// @Success 200 {array} domain.Song "Список песен"
-// @Failure 400 {object} ErrorResponse "Некорректные параметры запроса"
+// @Param lyrics_match query string false "Режим сравнения текста песни" Enums(contains, prefix, exact)
+// @Param sort query string false "Сортировка: поля через запятую, минус - по убыванию (id, group_name, song_name, release_date, link, relevance, similarity)" example(-release_date,group_name)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество записей на странице" default(10) maximum(100)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа); при наличии page не учитывается"
+// @Success 200 {object} SongListResponse "Страница списка песен"
+// @Failure 400 {object} FieldErrorResponse "Некорректные параметры запроса"
 // @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
 // @Router /songs [get]
 func (h *Handlers) GetSongs(c echo.Context) error {
 	h.logger.Info("GetSongs called")
 
 	// Получаем параметры фильтрации
-	groupName := c.QueryParam("group_name")
-	songName := c.QueryParam("song_name")
-	releaseDate := c.QueryParam("release_date")
-	lyrics := c.QueryParam("lyrics")
-	page, err := strconv.Atoi(c.QueryParam("page"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-	limit, err := strconv.Atoi(c.QueryParam("limit"))
-	if err != nil || limit < 1 {
-		limit = 10
-	}
-
-	// Параметры для фильтрации
-	params := map[string][]string{
-		"group_name":   {groupName},
-		"song_name":    {songName},
-		"release_date": {releaseDate},
-		"lyrics":       {lyrics},
-	}
-	h.logger.Info("GetSongs, params:", params)
-
-	// Получаем список песен с фильтрацией и пагинацией из сервиса
-	songs, err := h.service.GetSongs(params)
+	filter, err := songFilterFromQuery(c)
 	if err != nil {
+		h.logger.Warn("Invalid filter params", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	return h.listSongs(c, filter)
+}
+
+// listSongs отвечает страницей песен, удовлетворяющих фильтру, с параметрами
+// сортировки и пагинации из запроса.
+func (h *Handlers) listSongs(c echo.Context, filter repository.SongFilter) error {
+	sort, err := repository.ParseSort(c.QueryParam("sort"))
+	if err != nil {
+		h.logger.Warn("Invalid sort param", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректный параметр сортировки", Field: "sort"})
+	}
+	page, limit, err := pageParams(c)
+	if err != nil {
+		h.logger.Warn("Invalid page param", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	h.logger.Info("GetSongs",
+		slog.Any("filter", filter),
+		slog.String("sort", repository.FormatSort(sort)),
+		slog.Int("page", page),
+		slog.Int("limit", limit),
+	)
+
+	// Получаем страницу списка песен с фильтрацией из сервиса
+	list, err := h.service.GetSongs(service.SongQuery{
+		Filter: filter,
+		Sort:   sort,
+		Page:   page,
+		Limit:  limit,
+		Cursor: c.QueryParam("cursor"),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSort) {
+			h.logger.Warn("Invalid sort param", slog.Any("error", err))
+			return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректный параметр сортировки", Field: "sort"})
+		}
+		if errors.Is(err, service.ErrInvalidCursor) {
+			h.logger.Warn("Invalid cursor", slog.String("cursor", c.QueryParam("cursor")))
+			return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректный курсор", Field: "cursor"})
+		}
 		h.logger.Error("Failed to get songs", slog.Any("error", err))
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
 	}
-	h.logger.Info("GetSongs", slog.Any("songs", songs))
 
-	// Пагинация
-	start := (page - 1) * limit
-	end := start + limit
-	if start > len(songs) {
-		return c.JSON(http.StatusOK, []domain.Song{})
+	return c.JSON(http.StatusOK, SongListResponse{
+		Items:      list.Items,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		Total:      list.Total,
+		TotalPages: list.TotalPages(),
+		NextCursor: list.NextCursor,
+		DidYouMean: list.DidYouMean,
+	})
+}
+
+const (
+	defaultVersesPerPage = 1
+	maxVersesPerPage     = 50
+)
+
+type SongTextResponse struct {
+	ID            string          `json:"id" example:"1"`
+	Group         string          `json:"group" example:"Muse"`
+	Title         string          `json:"title" example:"Supermassive Black Hole"`
+	ReleaseDate   string          `json:"release_date" example:"2006-07-16"`
+	Link          string          `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	Verse         int             `json:"verse" example:"1"`
+	VersesPerPage int             `json:"verses_per_page" example:"1"`
+	TotalVerses   int             `json:"total_verses" example:"4"`
+	Stanzas       []domain.Stanza `json:"stanzas"`
+}
+
+// GetSong возвращает песню по её ID.
+// @Summary Получить песню
+// @Description Возвращает все данные песни. Поддерживаются условные запросы по ETag (If-None-Match)
+// @Description и Last-Modified (If-Modified-Since).
+// @Description Для совместимости с прежней версией API при view=lyrics или параметре verse
+// @Description возвращается страница текста песни, как в GET /songs/{id}/lyrics.
+// @Tags songs
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Param view query string false "lyrics - вернуть страницу текста песни (устарело)" Enums(lyrics)
+// @Param If-None-Match header string false "ETag сохранённой у клиента версии песни"
+// @Success 200 {object} domain.Song "Песня"
+// @Header 200 {string} ETag "Версия данных песни"
+// @Header 200 {string} Last-Modified "Время последнего изменения песни"
+// @Success 304 "Песня не изменилась"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID песни"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /songs/{id} [get]
+func (h *Handlers) GetSong(c echo.Context) error {
+	if legacyLyricsRequested(c) {
+		// Прежние клиенты получали по этому адресу текст песни
+		c.Response().Header().Set("Deprecation", "true")
+		if id, err := songIDParam(c); err == nil {
+			c.Response().Header().Set("Link", "</songs/"+id+"/lyrics>; rel=\"successor-version\"")
+		}
+		return h.GetSongLyrics(c)
 	}
-	if end > len(songs) {
-		end = len(songs)
+
+	h.logger.Info("GetSong called", slog.String("song_id", c.Param("id")))
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
 	}
-	if songs[start:end] == nil {
-		return c.JSON(http.StatusOK, []domain.Song{})
+
+	song, err := h.service.GetSong(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
+		}
+		h.logger.Error("Failed to get song", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Ошибка при получении песни"})
 	}
 
-	return c.JSON(http.StatusOK, songs[start:end])
+	etag := songETag(song)
+	setValidators(c, etag, song.UpdatedAt)
+	if notModified(c, etag, song.UpdatedAt) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, song)
+}
+
+// legacyLyricsRequested сообщает, ожидает ли клиент от GET /songs/{id}
+// текст песни, как в прежней версии API.
+func legacyLyricsRequested(c echo.Context) bool {
+	query := c.QueryParams()
+	return query.Get("view") == "lyrics" || query.Has("verse") || query.Has("verses_per_page")
 }
 
-// GetSongText возвращает текст песни с пагинацией по куплетам.
+// GetSongLyrics возвращает текст песни с пагинацией по куплетам.
 // @Summary Получить текст песни
-// @Description Возвращает текст песни с возможностью пагинации по куплетам.
+// @Description Возвращает данные песни и verses_per_page строф её текста, начиная со строфы verse.
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param id path string true "ID песни"
-// @Param verse query int false "Номер куплета"
-// @Success 200 {string} string "Текст песни"
-// @Failure 400 {object} ErrorResponse "Некорректный ID песни"
+// @Param id path int true "ID песни"
+// @Param verse query int false "Номер первой строфы" default(1) minimum(1)
+// @Param verses_per_page query int false "Количество строф на странице" default(1) maximum(50)
+// @Success 200 {object} SongTextResponse "Страница текста песни"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID песни или номер строфы"
 // @Failure 404 {object} ErrorResponse "Песня не найдена"
 // @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
-// @Router /songs/{id} [get]
-func (h *Handlers) GetSongText(c echo.Context) error {
-	h.logger.Info("GetSongText called", slog.String("song_id", c.Param("id")))
-	songId := c.Param("id")
-	verseStr := c.QueryParam("verse")
+// @Router /songs/{id}/lyrics [get]
+func (h *Handlers) GetSongLyrics(c echo.Context) error {
+	h.logger.Info("GetSongLyrics called", slog.String("song_id", c.Param("id")))
+	songId, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	verse := 1
+	if value := c.QueryParam("verse"); value != "" {
+		verse, err = strconv.Atoi(value)
+		if err != nil || verse < 1 {
+			return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Номер строфы должен быть положительным целым числом", Field: "verse"})
+		}
+	}
+	perPage, err := strconv.Atoi(c.QueryParam("verses_per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultVersesPerPage
+	}
+	if perPage > maxVersesPerPage {
+		perPage = maxVersesPerPage
+	}
+
+	page, err := h.service.GetSongVersePage(songId, verse, perPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
+		case errors.Is(err, service.ErrVerseOutOfRange):
+			h.logger.Warn("Verse out of range", slog.String("song_id", songId), slog.Any("error", err))
+			return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Строфы с таким номером нет", Field: "verse"})
+		}
+		h.logger.Error("Failed to get song text", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Ошибка при получении песни"})
+	}
+
+	return c.JSON(http.StatusOK, SongTextResponse{
+		ID:            page.Song.ID,
+		Group:         page.Song.Group,
+		Title:         page.Song.Title,
+		ReleaseDate:   page.Song.ReleaseDate,
+		Link:          page.Song.Link,
+		Verse:         page.Verse,
+		VersesPerPage: page.PerPage,
+		TotalVerses:   page.Total,
+		Stanzas:       page.Stanzas,
+	})
+}
+
+type SongVersesResponse struct {
+	SongID      string          `json:"song_id" example:"1"`
+	TotalVerses int             `json:"total_verses" example:"4"`
+	Stanzas     []domain.Stanza `json:"stanzas"`
+}
 
-	verse, err := strconv.Atoi(verseStr)
-	if err != nil || verse < 1 {
-		verse = 1
+// GetSongVerses возвращает текст песни, разобранный на строфы.
+// @Summary Получить строфы песни
+// @Description Возвращает количество строф и строфы текста песни с их видом (куплет, припев и т.п.),
+// @Description определённым по меткам вида [Chorus] или [Verse 2].
+// @Tags songs
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Success 200 {object} SongVersesResponse "Строфы песни"
+// @Failure 400 {object} ErrorResponse "Некорректный ID песни"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses [get]
+func (h *Handlers) GetSongVerses(c echo.Context) error {
+	h.logger.Info("GetSongVerses called", slog.String("song_id", c.Param("id")))
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Некорректный ID песни"})
 	}
-	lyrics, err := h.service.GetSongLyrics(songId, verseStr)
+
+	song, err := h.service.GetSongVerses(id)
 	if err != nil {
-		h.logger.Info("GetSongText Lyrics failed", slog.String("err", err.Error()))
 		if errors.Is(err, repository.ErrNotFound) {
-			return c.JSON(http.StatusNotFound, "Песня не найдена")
+			h.logger.Warn("Song not found", slog.String("song_id", id))
+			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
 		}
-		return c.JSON(http.StatusInternalServerError, "Ошибка при получении песни")
+		h.logger.Error("Failed to get song verses", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Ошибка при получении песни"})
 	}
 
-	return c.JSON(http.StatusOK, lyrics)
+	return c.JSON(http.StatusOK, SongVersesResponse{
+		SongID:      song.ID,
+		TotalVerses: len(song.Stanzas),
+		Stanzas:     song.Stanzas,
+	})
 }
 
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLen     = 255
+	headerPrefer             = "Prefer"
+	preferRespondAsync       = "respond-async"
+)
+
 type AddSongRequest struct {
 	Group string `json:"group" example:"Muse"`
 	Title string `json:"song" example:"Supermassive Black Hole"`
@@ -146,13 +395,26 @@ type ExternalAPISongResponse struct {
 // AddSong добавляет новую песню в библиотеку.
 // @Summary Добавить новую песню
 // @Description Добавляет новую песню в библиотеку и получает информацию о песне из внешнего API.
+// @Description В асинхронном режиме (async=true или заголовок Prefer: respond-async) песня сохраняется сразу
+// @Description со статусом enrichment_status = pending, а информация из внешнего API запрашивается в фоне;
+// @Description результат можно узнать по адресу из заголовка Location.
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом вернёт уже добавленную песню"
+// @Param Prefer header string false "respond-async - добавить песню асинхронно"
+// @Param async query bool false "Добавить песню асинхронно"
 // @Param song body AddSongRequest true "Песня (группа и название)"
 // @Success 201 {object} domain.Song "Песня добавлена с детальной информацией"
+// @Success 202 {object} domain.Song "Песня добавлена, информация из внешнего API будет получена позже"
+// @Header 201,202 {string} Location "Адрес добавленной песни: /songs/{id}"
+// @Header 201,202 {string} Idempotent-Replayed "true, если возвращён результат предыдущего запроса с тем же ключом"
 // @Failure 400 {object} ErrorResponse "Некорректные данные песни"
+// @Failure 409 {object} ConflictResponse "Песня уже существует или запрос с этим ключом ещё выполняется"
+// @Failure 404 {object} ErrorResponse "Песня не найдена во внешнем API"
+// @Failure 422 {object} ErrorResponse "Ключ идемпотентности использован с другими данными"
 // @Failure 500 {object} ErrorResponse "Не удалось добавить песню"
+// @Failure 503 {object} ErrorResponse "Внешний API временно недоступен"
 // @Router /songs [post]
 func (h *Handlers) AddSong(c echo.Context) error {
 	h.logger.Info("AddSong called")
@@ -160,28 +422,90 @@ func (h *Handlers) AddSong(c echo.Context) error {
 	// Парсим запрос от клиента
 	var addSongRequest AddSongRequest
 	if err := c.Bind(&addSongRequest); err != nil {
-		h.logger.Error("Invalid song data", err)
+		h.logger.Error("Invalid song data", slog.Any("error", err))
 		return c.JSON(http.StatusBadRequest, ErrorResponse{"Некорректные данные песни"})
 	}
-	h.logger.Info(addSongRequest.Group, addSongRequest.Title)
+	h.logger.Info("AddSong request", slog.String("group", addSongRequest.Group), slog.String("song", addSongRequest.Title))
 
 	// Проверяем наличие группы и названия песни
+	addSongRequest.Group = strings.TrimSpace(addSongRequest.Group)
+	addSongRequest.Title = strings.TrimSpace(addSongRequest.Title)
 	if addSongRequest.Group == "" || addSongRequest.Title == "" {
 		h.logger.Warn("Group or song title is missing")
 		return c.JSON(http.StatusBadRequest, ErrorResponse{"Группа и название песни обязательны"})
 	}
+	idempotencyKey, err := idempotencyKeyParam(c)
+	if err != nil {
+		h.logger.Warn("Invalid idempotency key", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Слишком длинный ключ идемпотентности"})
+	}
+	async, err := asyncRequested(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Параметр async должен быть true или false"})
+	}
 
 	// Вызываем метод сервиса для добавления песни
-	newSong, err := h.service.AddSong(addSongRequest.Group, addSongRequest.Title, h.cfg.API.MusicInfoURL)
+	ctx := c.Request().Context()
+	var newSong *domain.Song
+	var replayed bool
+	switch {
+	case idempotencyKey != "":
+		newSong, replayed, err = h.service.AddSongIdempotent(ctx, idempotencyKey, addSongRequest.Group, addSongRequest.Title, async)
+	case async:
+		newSong, err = h.service.AddSongAsync(addSongRequest.Group, addSongRequest.Title)
+	default:
+		newSong, err = h.service.AddSong(ctx, addSongRequest.Group, addSongRequest.Title)
+	}
 	if err != nil {
-		h.logger.Error("Failed to add song", err)
+		var duplicate *repository.DuplicateError
+		switch {
+		case errors.As(err, &duplicate):
+			h.logger.Warn("Song already exists", slog.String("song_id", duplicate.ExistingID))
+			return c.JSON(http.StatusConflict, ConflictResponse{Message: "Песня уже существует", ID: duplicate.ExistingID})
+		case errors.Is(err, service.ErrRequestInProgress):
+			h.logger.Warn("Request with idempotency key is in progress", slog.String("key", idempotencyKey))
+			return c.JSON(http.StatusConflict, ConflictResponse{Message: "Запрос с этим ключом идемпотентности ещё выполняется"})
+		case errors.Is(err, service.ErrIdempotencyKeyMismatch):
+			h.logger.Warn("Idempotency key reused with another request", slog.String("key", idempotencyKey))
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{"Ключ идемпотентности уже использован с другими данными"})
+		case errors.Is(err, musicinfo.ErrNotFound):
+			h.logger.Warn("Song not found in music info API")
+			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена во внешнем API"})
+		case errors.Is(err, musicinfo.ErrUnavailable):
+			h.logger.Error("Music info API is unavailable")
+			return c.JSON(http.StatusServiceUnavailable, ErrorResponse{"Внешний API временно недоступен"})
+		}
+		h.logger.Error("Failed to add song", slog.Any("error", err))
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Не удалось добавить песню"})
 	}
+	if replayed {
+		c.Response().Header().Set(headerIdempotentReplayed, "true")
+	}
 
-	// Возвращаем добавленную песню
+	// Возвращаем добавленную песню со ссылкой на неё
+	c.Response().Header().Set(echo.HeaderLocation, "/songs/"+newSong.ID)
+	if newSong.EnrichmentStatus == domain.EnrichmentPending {
+		return c.JSON(http.StatusAccepted, newSong)
+	}
 	return c.JSON(http.StatusCreated, newSong)
 }
 
+// asyncRequested сообщает, просит ли клиент добавить песню асинхронно:
+// параметром async=true или заголовком Prefer: respond-async.
+func asyncRequested(c echo.Context) (bool, error) {
+	if value := c.QueryParam("async"); value != "" {
+		return strconv.ParseBool(value)
+	}
+	for _, prefer := range c.Request().Header.Values(headerPrefer) {
+		for _, token := range strings.Split(prefer, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), preferRespondAsync) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 type UpdateSongRequest struct {
 	Group       string `json:"group" example:"Muse"`
 	Title       string `json:"title" example:"Supermassive Black Hole"`
@@ -190,83 +514,186 @@ type UpdateSongRequest struct {
 	Link        string `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
 }
 
-// UpdateSong обновляет данные существующей песни.
-// @Summary Изменить данные песни
-// @Description Обновляет информацию о песне, включая название группы, название песни, текст, дату релиза и ссылку.
+// UpdateSong заменяет данные существующей песни.
+// @Summary Заменить данные песни
+// @Description Заменяет все данные песни: группа и название обязательны, незаданные дата релиза,
+// @Description текст и ссылка очищаются. Для изменения отдельных полей используйте PATCH.
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param id path string true "ID песни"
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни из GET /songs/{id}"
 // @Param song body UpdateSongRequest true "Новая информация о песне"
 // @Success 200 {object} SuccessResponse "Данные песни обновлены"
-// @Failure 400 {object} ErrorResponse "Некорректные данные песни"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID или данные песни"
 // @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 409 {object} ConflictResponse "Песня с такими группой и названием уже существует"
 // @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 412 {object} ErrorResponse "Песня изменена после получения ETag"
+// @Failure 428 {object} ErrorResponse "Требуется заголовок If-Match"
 // @Router /songs/{id} [put]
 func (h *Handlers) UpdateSong(c echo.Context) error {
 	h.logger.Info("UpdateSong called", slog.String("song_id", c.Param("id")))
 
 	// Получаем ID песни из параметров пути
-	id := c.Param("id")
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
 
-	// Парсинг данных обновления из тела запроса
+	// Парсинг данных песни из тела запроса
 	var updateReq UpdateSongRequest
 	if err := c.Bind(&updateReq); err != nil {
 		h.logger.Error("Invalid song data", slog.Any("error", err))
-		return c.JSON(http.StatusBadRequest, ErrorResponse{"Некорректные данные песни"})
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные песни"})
 	}
 
-	// Подготовка данных для обновления
-	updates := map[string]string{}
-	if updateReq.Group != "" {
-		updates["group_name"] = updateReq.Group
-	}
-	if updateReq.Title != "" {
-		updates["song_name"] = updateReq.Title
+	versions, err := ifMatchVersions(c, h.cfg.HTTPServer.RequireIfMatch)
+	if handled, resp := preconditionError(c, err); handled {
+		return resp
 	}
-	if updateReq.Lyrics != "" {
-		updates["lyrics"] = updateReq.Lyrics
-	}
-	if updateReq.ReleaseDate != "" {
-		updates["release_date"] = updateReq.ReleaseDate
+
+	// Заменяются все поля, в том числе пустыми значениями
+	upd := repository.SongUpdate{
+		Group:       &updateReq.Group,
+		Title:       &updateReq.Title,
+		ReleaseDate: &updateReq.ReleaseDate,
+		Lyrics:      &updateReq.Lyrics,
+		Link:        &updateReq.Link,
 	}
-	if updateReq.Link != "" {
-		updates["link"] = updateReq.Link
+	if err := validateSongUpdate(&upd); err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
 	}
 
 	// Обновляем песню через сервис
-	err := h.service.UpdateSong(id, updates)
+	song, err := h.service.UpdateSong(id, upd, versions)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			h.logger.Warn("Song not found", slog.String("song_id", id))
-			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
-		}
-		h.logger.Error("Failed to update song", slog.Any("error", err))
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Не удалось обновить песню"})
+		return h.updateSongError(c, id, err)
 	}
 
 	// Возвращаем успешный ответ
+	c.Response().Header().Set("ETag", songETag(song))
 	return c.JSON(http.StatusOK, SuccessResponse{"Данные песни обновлены"})
 }
 
+// SongPatchRequest - изменения песни в формате JSON Merge Patch (RFC 7396).
+// Отсутствующие поля не изменяются, null очищает поле.
+type SongPatchRequest struct {
+	Group       *string `json:"group,omitempty" example:"Muse"`
+	Title       *string `json:"title,omitempty" example:"Supermassive Black Hole"`
+	ReleaseDate *string `json:"release_date,omitempty" example:"2006-07-16"`
+	Lyrics      *string `json:"lyrics,omitempty" example:"Ooh baby, don't you know I suffer? ..."`
+	Link        *string `json:"link,omitempty"`
+}
+
+const mimeMergePatch = "application/merge-patch+json"
+
+// PatchSong изменяет отдельные поля песни.
+// @Summary Изменить отдельные поля песни
+// @Description Применяет к песне JSON Merge Patch (RFC 7396): изменяются только переданные поля,
+// @Description null очищает дату релиза, текст или ссылку. Группу и название очистить нельзя.
+// @Tags songs
+// @Accept  json,application/merge-patch+json
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни из GET /songs/{id}"
+// @Param patch body SongPatchRequest true "Изменяемые поля песни"
+// @Success 200 {object} domain.Song "Изменённая песня"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID или данные песни"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 409 {object} ConflictResponse "Песня с такими группой и названием уже существует"
+// @Failure 415 {object} ErrorResponse "Неподдерживаемый формат тела запроса"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 412 {object} ErrorResponse "Песня изменена после получения ETag"
+// @Failure 428 {object} ErrorResponse "Требуется заголовок If-Match"
+// @Router /songs/{id} [patch]
+func (h *Handlers) PatchSong(c echo.Context) error {
+	h.logger.Info("PatchSong called", slog.String("song_id", c.Param("id")))
+
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	contentType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if contentType != mimeMergePatch && contentType != echo.MIMEApplicationJSON {
+		return c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{"Ожидается тело запроса " + mimeMergePatch})
+	}
+
+	versions, err := ifMatchVersions(c, h.cfg.HTTPServer.RequireIfMatch)
+	if handled, resp := preconditionError(c, err); handled {
+		return resp
+	}
+
+	upd, err := parseMergePatch(c.Request().Body)
+	if err != nil {
+		h.logger.Warn("Invalid merge patch", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	if err := validateSongUpdate(&upd); err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	song, err := h.service.UpdateSong(id, upd, versions)
+	if err != nil {
+		return h.updateSongError(c, id, err)
+	}
+	c.Response().Header().Set("ETag", songETag(song))
+	return c.JSON(http.StatusOK, song)
+}
+
+// updateSongError формирует ответ на ошибку изменения песни.
+func (h *Handlers) updateSongError(c echo.Context, id string, err error) error {
+	if handled, resp := preconditionError(c, err); handled {
+		h.logger.Warn("Song version mismatch", slog.String("song_id", id))
+		return resp
+	}
+	var duplicate *repository.DuplicateError
+	if errors.As(err, &duplicate) {
+		h.logger.Warn("Song already exists", slog.String("song_id", duplicate.ExistingID))
+		return c.JSON(http.StatusConflict, ConflictResponse{Message: "Песня с такими группой и названием уже существует", ID: duplicate.ExistingID})
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		h.logger.Warn("Song not found", slog.String("song_id", id))
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
+	}
+	h.logger.Error("Failed to update song", slog.Any("error", err))
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{"Не удалось обновить песню"})
+}
+
 // DeleteSong удаляет песню из библиотеки.
 // @Summary Удалить песню
 // @Description Удаляет песню по ее ID.
 // @Tags songs
-// @Param id path string true "ID песни"
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни из GET /songs/{id}"
 // @Success 200 {object} SuccessResponse "Песня удалена"
+// @Failure 400 {object} ErrorResponse "Некорректный ID песни"
 // @Failure 404 {object} ErrorResponse "Песня не найдена"
 // @Failure 500 {object} ErrorResponse "Не удалось удалить песню"
+// @Failure 412 {object} ErrorResponse "Песня изменена после получения ETag"
+// @Failure 428 {object} ErrorResponse "Требуется заголовок If-Match"
 // @Router /songs/{id} [delete]
 func (h *Handlers) DeleteSong(c echo.Context) error {
 	h.logger.Info("DeleteSong called", slog.String("song_id", c.Param("id")))
 
 	// Получаем ID песни из параметров пути
-	id := c.Param("id")
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Некорректный ID песни"})
+	}
+
+	versions, err := ifMatchVersions(c, h.cfg.HTTPServer.RequireIfMatch)
+	if handled, resp := preconditionError(c, err); handled {
+		return resp
+	}
 
 	// Попытка удаления песни через сервис
-	err := h.service.DeleteSong(id)
+	err = h.service.DeleteSong(id, versions)
 	if err != nil {
+		if handled, resp := preconditionError(c, err); handled {
+			h.logger.Warn("Song version mismatch", slog.String("song_id", id))
+			return resp
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			h.logger.Warn("Song not found", slog.String("song_id", id))
 			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
@@ -279,3 +706,141 @@ func (h *Handlers) DeleteSong(c echo.Context) error {
 	h.logger.Info("Song deleted successfully", slog.String("song_id", id))
 	return c.JSON(http.StatusOK, SuccessResponse{"Песня успешно удалена"})
 }
+
+type RefreshSongResponse struct {
+	Song    *domain.Song         `json:"song"`
+	Changes []domain.FieldChange `json:"changes"`
+	Applied bool                 `json:"applied" example:"true"`
+}
+
+// RefreshSong заново получает данные песни из внешнего API.
+// @Summary Обновить данные песни из внешнего API
+// @Description Заново запрашивает дату релиза, текст и ссылку во внешнем API и сравнивает их с сохранёнными.
+// @Description По умолчанию полученные данные сохраняются; с dry_run=true возвращаются только изменения,
+// @Description а песня не изменяется. Заменяются только пустые поля и поля, полученные из внешнего API:
+// @Description значения, заданные через PUT или PATCH, и пустые значения из внешнего API не применяются.
+// @Tags songs
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Param dry_run query bool false "Только показать изменения, не сохраняя их"
+// @Success 200 {object} RefreshSongResponse "Песня и изменённые поля"
+// @Failure 400 {object} ErrorResponse "Некорректный запрос"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 500 {object} ErrorResponse "Не удалось обновить песню"
+// @Failure 503 {object} ErrorResponse "Внешний API временно недоступен"
+// @Router /songs/{id}/refresh [post]
+func (h *Handlers) RefreshSong(c echo.Context) error {
+	h.logger.Info("RefreshSong called", slog.String("song_id", c.Param("id")))
+
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Некорректный ID песни"})
+	}
+	dryRun := false
+	if value := c.QueryParam("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{"Параметр dry_run должен быть true или false"})
+		}
+	}
+
+	result, err := h.service.RefreshSong(c.Request().Context(), id, !dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			h.logger.Warn("Song not found", slog.String("song_id", id))
+			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена"})
+		case errors.Is(err, musicinfo.ErrNotFound):
+			h.logger.Warn("Song not found in music info API", slog.String("song_id", id))
+			return c.JSON(http.StatusNotFound, ErrorResponse{"Песня не найдена во внешнем API"})
+		case errors.Is(err, musicinfo.ErrUnavailable):
+			h.logger.Error("Music info API is unavailable")
+			return c.JSON(http.StatusServiceUnavailable, ErrorResponse{"Внешний API временно недоступен"})
+		}
+		h.logger.Error("Failed to refresh song", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Не удалось обновить песню"})
+	}
+
+	h.logger.Info("Song refreshed", slog.String("song_id", id), slog.Int("changes", len(result.Changes)), slog.Bool("applied", result.Applied))
+	return c.JSON(http.StatusOK, RefreshSongResponse{Song: result.Song, Changes: result.Changes, Applied: result.Applied})
+}
+
+const maxBulkDeleteIDs = 100
+
+type DeleteSongsRequest struct {
+	IDs []int64 `json:"ids" example:"1,2,3"`
+	// Версии песен (ID -> версия из ETag): песня удаляется, только если
+	// её версия совпадает. Обязательны для всех ID, если сервис требует If-Match
+	Versions map[int64]int64 `json:"versions,omitempty"`
+}
+
+// DeleteSongResult - результат удаления одной песни.
+type DeleteSongResult struct {
+	ID     int64  `json:"id" example:"1"`
+	Status string `json:"status" example:"deleted" enums:"deleted,not_found,version_mismatch"`
+}
+
+type DeleteSongsResponse struct {
+	Results []DeleteSongResult `json:"results"`
+}
+
+// DeleteSongs удаляет несколько песен из библиотеки.
+// @Summary Удалить несколько песен
+// @Description Удаляет песни по списку ID (не более 100) и возвращает результат для каждого ID: deleted, not_found
+// @Description или version_mismatch, если для песни в versions указана версия, а песня была изменена.
+// @Description Если сервис требует If-Match, версия обязательна для каждого ID.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param ids body DeleteSongsRequest true "ID удаляемых песен и их версии"
+// @Success 200 {object} DeleteSongsResponse "Результаты удаления"
+// @Failure 400 {object} ErrorResponse "Некорректный список ID"
+// @Failure 428 {object} ErrorResponse "Требуются версии песен"
+// @Failure 500 {object} ErrorResponse "Не удалось удалить песни"
+// @Router /songs [delete]
+func (h *Handlers) DeleteSongs(c echo.Context) error {
+	h.logger.Info("DeleteSongs called")
+
+	var req DeleteSongsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn("Invalid delete request", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Некорректный список ID"})
+	}
+	if len(req.IDs) == 0 || len(req.IDs) > maxBulkDeleteIDs {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{"Список ID должен содержать от 1 до 100 элементов"})
+	}
+	requested := make(map[int64]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if id < 1 || id > math.MaxInt32 {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{"ID песни должен быть положительным целым числом"})
+		}
+		requested[id] = true
+	}
+	for id := range req.Versions {
+		if !requested[id] {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{"Версия указана для песни, которой нет в списке ID"})
+		}
+	}
+	if h.cfg.HTTPServer.RequireIfMatch && len(req.Versions) < len(requested) {
+		h.logger.Warn("Song versions are required for bulk delete")
+		return c.JSON(http.StatusPreconditionRequired, ErrorResponse{"Требуется версия каждой удаляемой песни в versions"})
+	}
+
+	results, err := h.service.DeleteSongs(req.IDs, req.Versions)
+	if err != nil {
+		h.logger.Error("Ошибка при удалении песен", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Не удалось удалить песни"})
+	}
+
+	resp := DeleteSongsResponse{Results: make([]DeleteSongResult, len(results))}
+	for i, r := range results {
+		resp.Results[i] = DeleteSongResult{ID: r.ID, Status: "not_found"}
+		switch {
+		case r.Deleted:
+			resp.Results[i].Status = "deleted"
+		case r.VersionMismatch:
+			resp.Results[i].Status = "version_mismatch"
+		}
+	}
+	h.logger.Info("Songs deleted", slog.Int("requested", len(results)))
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,394 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// paramError - ошибка разбора конкретного параметра запроса.
+type paramError struct {
+	param   string
+	message string
+}
+
+func (e *paramError) Error() string {
+	return fmt.Sprintf("%s: %s", e.param, e.message)
+}
+
+// newFieldErrorResponse формирует ответ об ошибке в параметре запроса.
+func newFieldErrorResponse(err error) FieldErrorResponse {
+	var pe *paramError
+	if errors.As(err, &pe) {
+		return FieldErrorResponse{Message: pe.message, Field: pe.param}
+	}
+	return FieldErrorResponse{Message: "Некорректные параметры запроса"}
+}
+
+// songFilterFromQuery собирает фильтр песен из query-параметров запроса.
+func songFilterFromQuery(c echo.Context) (repository.SongFilter, error) {
+	filter := repository.SongFilter{Query: strings.TrimSpace(c.QueryParam("q"))}
+	fields := []struct {
+		param string
+		dst   *repository.StringFilter
+	}{
+		{"group_name", &filter.Group},
+		{"song_name", &filter.Title},
+		{"lyrics", &filter.Lyrics},
+	}
+	for _, f := range fields {
+		param := f.param + "_match"
+		mode, err := repository.ParseMatchMode(c.QueryParam(param))
+		if err != nil {
+			return filter, &paramError{param, "Допустимые значения: contains, prefix, exact"}
+		}
+		*f.dst = repository.StringFilter{Value: c.QueryParam(f.param), Mode: mode}
+	}
+
+	if value := c.QueryParam("fuzzy"); value != "" {
+		fuzzy, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, &paramError{"fuzzy", "Ожидается true или false"}
+		}
+		filter.Fuzzy = fuzzy
+	}
+
+	dates := []struct {
+		param string
+		dst   *time.Time
+	}{
+		{"release_date", &filter.ReleaseDate},
+		{"release_date_from", &filter.ReleaseDateFrom},
+		{"release_date_to", &filter.ReleaseDateTo},
+	}
+	for _, d := range dates {
+		value := c.QueryParam(d.param)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(repository.DateLayout, value)
+		if err != nil {
+			return filter, &paramError{d.param, "Некорректная дата, ожидается формат YYYY-MM-DD"}
+		}
+		*d.dst = date
+	}
+	if !filter.ReleaseDateFrom.IsZero() && !filter.ReleaseDateTo.IsZero() &&
+		filter.ReleaseDateTo.Before(filter.ReleaseDateFrom) {
+		return filter, &paramError{"release_date_to", "Дата окончания периода раньше даты начала"}
+	}
+
+	if value := c.QueryParam("release_year"); value != "" {
+		year, err := parseYear(value)
+		if err != nil {
+			return filter, &paramError{"release_year", "Некорректный год, ожидается число от 1 до 9989"}
+		}
+		filter.ReleaseYear = year
+	}
+	if value := c.QueryParam("decade"); value != "" {
+		decade, err := parseDecade(value)
+		if err != nil {
+			return filter, &paramError{"decade", "Некорректное десятилетие, ожидается год, кратный 10, например 1990 или 1990s"}
+		}
+		filter.Decade = decade
+	}
+	if value := c.QueryParam("artist_id"); value != "" {
+		id, ok := parseID(value)
+		if !ok {
+			return filter, &paramError{"artist_id", "ID исполнителя должен быть положительным целым числом"}
+		}
+		filter.ArtistID = id
+	}
+	if value := c.QueryParam("album_id"); value != "" {
+		id, ok := parseID(value)
+		if !ok {
+			return filter, &paramError{"album_id", "ID альбома должен быть положительным целым числом"}
+		}
+		filter.AlbumID = id
+	}
+	if value := c.QueryParam("credited_artist_id"); value != "" {
+		id, ok := parseID(value)
+		if !ok {
+			return filter, &paramError{"credited_artist_id", "ID исполнителя должен быть положительным целым числом"}
+		}
+		filter.CreditArtistID = id
+	}
+	if value := c.QueryParam("credit_role"); value != "" {
+		role := domain.CreditRole(strings.ToLower(value))
+		if !role.Valid() {
+			return filter, &paramError{"credit_role", "Допустимые значения: primary, featured, composer, lyricist, producer"}
+		}
+		filter.CreditRole = role
+	}
+
+	filter.Genres = listParam(c, "genre")
+	if len(filter.Genres) > maxFilterValues {
+		return filter, &paramError{"genre", fmt.Sprintf("Не более %d жанров", maxFilterValues)}
+	}
+	if value := c.QueryParam("include_subgenres"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, &paramError{"include_subgenres", "Ожидается true или false"}
+		}
+		filter.IncludeSubgenres = include
+	}
+	filter.Tags = listParam(c, "tag")
+	if len(filter.Tags) > maxFilterValues {
+		return filter, &paramError{"tag", fmt.Sprintf("Не более %d тегов", maxFilterValues)}
+	}
+	switch strings.ToLower(c.QueryParam("tag_mode")) {
+	case "", "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		return filter, &paramError{"tag_mode", "Допустимые значения: any, all"}
+	}
+	return filter, nil
+}
+
+// maxFilterValues - максимальное количество значений в фильтрах genre и tag.
+const maxFilterValues = 20
+
+// listParam возвращает значения query-параметра, заданного несколько раз
+// и/или через запятую, без пустых значений и повторов.
+func listParam(c echo.Context, name string) []string {
+	var values []string
+	seen := map[string]bool{}
+	for _, param := range c.QueryParams()[name] {
+		for _, v := range strings.Split(param, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" || seen[strings.ToLower(v)] {
+				continue
+			}
+			seen[strings.ToLower(v)] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseYear разбирает год релиза. Верхняя граница оставляет запас
+// для вычисления конца периода в формате YYYY-MM-DD.
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if year < 1 || year > 9989 {
+		return 0, fmt.Errorf("year out of range: %d", year)
+	}
+	return year, nil
+}
+
+// parseDecade разбирает десятилетие вида "1990" или "1990s".
+func parseDecade(s string) (int, error) {
+	year, err := parseYear(strings.TrimSuffix(strings.ToLower(s), "s"))
+	if err != nil {
+		return 0, err
+	}
+	if year%10 != 0 {
+		return 0, fmt.Errorf("decade must start with a year divisible by 10: %d", year)
+	}
+	return year, nil
+}
+
+// songIDParam возвращает ID песни из пути запроса.
+// ID должен быть положительным целым числом.
+func songIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("id"))
+	if !ok {
+		return "", &paramError{"id", "ID песни должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// artistIDParam возвращает ID исполнителя из пути запроса.
+func artistIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("id"))
+	if !ok {
+		return "", &paramError{"id", "ID исполнителя должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// albumIDParam возвращает ID альбома из пути запроса.
+func albumIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("id"))
+	if !ok {
+		return "", &paramError{"id", "ID альбома должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// genreIDParam возвращает ID жанра из пути запроса.
+func genreIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("id"))
+	if !ok {
+		return "", &paramError{"id", "ID жанра должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// tagIDParam возвращает ID тега из пути запроса.
+func tagIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("id"))
+	if !ok {
+		return "", &paramError{"id", "ID тега должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// playlistIDParam возвращает ID плейлиста из пути запроса.
+func playlistIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("id"))
+	if !ok {
+		return "", &paramError{"id", "ID плейлиста должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// entryIDParam возвращает ID записи плейлиста из пути запроса.
+func entryIDParam(c echo.Context) (string, error) {
+	id, ok := parseID(c.Param("entry_id"))
+	if !ok {
+		return "", &paramError{"entry_id", "ID записи должен быть положительным целым числом"}
+	}
+	return id, nil
+}
+
+// maxPageOffset - наибольшее количество записей, пропускаемых до страницы:
+// более далёкие страницы выбираются слишком долго (для песен есть курсоры).
+const maxPageOffset = 100000
+
+// pageParams возвращает номер страницы и количество записей на странице из
+// параметров page и limit. Некорректные значения заменяются значениями по
+// умолчанию, а limit ограничивается maxPageLimit. Если страница дальше
+// maxPageOffset записей, возвращает *paramError.
+func pageParams(c echo.Context) (page, limit int, err error) {
+	page, err = strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err = strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	// Сравнение без умножения: (page-1)*limit может переполниться
+	if maxPage := maxPageOffset/limit + 1; page > maxPage {
+		return 0, 0, &paramError{"page", fmt.Sprintf("Номер страницы при limit=%d должен быть не больше %d", limit, maxPage)}
+	}
+	return page, limit, nil
+}
+
+// parseID разбирает ID - положительное целое число в пределах столбца
+// INTEGER - и возвращает его в каноническом виде (без знака и ведущих нулей).
+func parseID(s string) (string, bool) {
+	id, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || id < 1 {
+		return "", false
+	}
+	return strconv.FormatInt(id, 10), true
+}
+
+// idempotencyKeyParam возвращает ключ идемпотентности из заголовка запроса.
+// Пустая строка означает, что клиент не передал ключ.
+func idempotencyKeyParam(c echo.Context) (string, error) {
+	key := c.Request().Header.Get(headerIdempotencyKey)
+	if len(key) > maxIdempotencyKeyLen {
+		return "", &paramError{headerIdempotencyKey, "Слишком длинный ключ идемпотентности"}
+	}
+	return key, nil
+}
+
+// Максимальная длина полей песни (по размеру столбцов в базе данных)
+const (
+	maxSongNameLen = 100
+	maxSongLinkLen = 255
+)
+
+// validateSongUpdate проверяет изменения песни и убирает пробелы по краям
+// группы и названия.
+func validateSongUpdate(upd *repository.SongUpdate) error {
+	for _, f := range []struct {
+		param string
+		value *string
+	}{
+		{"group", upd.Group},
+		{"title", upd.Title},
+	} {
+		if f.value == nil {
+			continue
+		}
+		*f.value = strings.TrimSpace(*f.value)
+		if *f.value == "" {
+			return &paramError{f.param, "Поле обязательно"}
+		}
+		if utf8.RuneCountInString(*f.value) > maxSongNameLen {
+			return &paramError{f.param, fmt.Sprintf("Не более %d символов", maxSongNameLen)}
+		}
+	}
+	if upd.ReleaseDate != nil && *upd.ReleaseDate != "" {
+		if _, err := time.Parse(repository.DateLayout, *upd.ReleaseDate); err != nil {
+			return &paramError{"release_date", "Некорректная дата, ожидается формат YYYY-MM-DD"}
+		}
+	}
+	if upd.Link != nil && utf8.RuneCountInString(*upd.Link) > maxSongLinkLen {
+		return &paramError{"link", fmt.Sprintf("Не более %d символов", maxSongLinkLen)}
+	}
+	return nil
+}
+
+// mergePatchFields - поля песни, которые можно изменить через JSON Merge Patch,
+// и допускается ли для них null.
+var mergePatchFields = map[string]bool{
+	"group":        false,
+	"title":        false,
+	"release_date": true,
+	"lyrics":       true,
+	"link":         true,
+}
+
+// parseMergePatch разбирает изменения песни в формате JSON Merge Patch (RFC 7396):
+// отсутствующие поля не изменяются, null очищает поле.
+func parseMergePatch(body io.Reader) (repository.SongUpdate, error) {
+	var upd repository.SongUpdate
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&patch); err != nil {
+		return upd, &paramError{"", "Тело запроса должно быть объектом JSON"}
+	}
+
+	fields := map[string]**string{
+		"group":        &upd.Group,
+		"title":        &upd.Title,
+		"release_date": &upd.ReleaseDate,
+		"lyrics":       &upd.Lyrics,
+		"link":         &upd.Link,
+	}
+	for name, raw := range patch {
+		nullable, ok := mergePatchFields[name]
+		if !ok {
+			return upd, &paramError{name, "Неизвестное поле"}
+		}
+		// null очищает поле: пустая строка в SongUpdate
+		value := new(string)
+		if string(raw) == "null" {
+			if !nullable {
+				return upd, &paramError{name, "Поле нельзя очистить"}
+			}
+		} else if err := json.Unmarshal(raw, value); err != nil {
+			return upd, &paramError{name, "Ожидается строка или null"}
+		}
+		*fields[name] = value
+	}
+	return upd, nil
+}
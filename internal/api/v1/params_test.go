@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIdempotencyKeyParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []string
+		want    string
+		wantErr bool
+	}{
+		{name: "no header", want: ""},
+		{name: "key", header: []string{"8f14e45f-ceea-467a-9af0-fd0e5e1f4c3b"}, want: "8f14e45f-ceea-467a-9af0-fd0e5e1f4c3b"},
+		{name: "longest key", header: []string{strings.Repeat("k", maxIdempotencyKeyLen)}, want: strings.Repeat("k", maxIdempotencyKeyLen)},
+		{name: "too long", header: []string{strings.Repeat("k", maxIdempotencyKeyLen+1)}, wantErr: true},
+		{name: "first of several headers", header: []string{"first", "second"}, want: "first"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/songs", nil)
+			for _, v := range tt.header {
+				req.Header.Add(headerIdempotencyKey, v)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			got, err := idempotencyKeyParam(c)
+			if tt.wantErr {
+				var pe *paramError
+				if !errors.As(err, &pe) || pe.param != headerIdempotencyKey {
+					t.Fatalf("idempotencyKeyParam() error = %v, want *paramError for %s", err, headerIdempotencyKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("idempotencyKeyParam(): %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("idempotencyKeyParam() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMergePatch(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name      string
+		body      string
+		want      repository.SongUpdate
+		wantField string
+		wantErr   bool
+	}{
+		{name: "empty object", body: `{}`},
+		{
+			name: "set fields",
+			body: `{"group": "Muse", "title": "Uprising", "release_date": "07.09.2009"}`,
+			want: repository.SongUpdate{Group: str("Muse"), Title: str("Uprising"), ReleaseDate: str("07.09.2009")},
+		},
+		{
+			name: "null clears field",
+			body: `{"link": null, "lyrics": null, "release_date": null}`,
+			want: repository.SongUpdate{Link: str(""), Lyrics: str(""), ReleaseDate: str("")},
+		},
+		{
+			name: "empty string is kept as a value",
+			body: `{"lyrics": ""}`,
+			want: repository.SongUpdate{Lyrics: str("")},
+		},
+		{name: "required field cannot be cleared", body: `{"group": null}`, wantErr: true, wantField: "group"},
+		{name: "title cannot be cleared", body: `{"title": null}`, wantErr: true, wantField: "title"},
+		{name: "unknown field", body: `{"id": "1"}`, wantErr: true, wantField: "id"},
+		{name: "not a string", body: `{"link": 42}`, wantErr: true, wantField: "link"},
+		{name: "nested object", body: `{"lyrics": {"text": "Ooh"}}`, wantErr: true, wantField: "lyrics"},
+		{name: "array body", body: `[{"group": "Muse"}]`, wantErr: true},
+		{name: "invalid json", body: `{"group": `, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMergePatch(strings.NewReader(tt.body))
+			if tt.wantErr {
+				var pe *paramError
+				if !errors.As(err, &pe) {
+					t.Fatalf("parseMergePatch(%s) error = %v, want *paramError", tt.body, err)
+				}
+				if pe.param != tt.wantField {
+					t.Errorf("parseMergePatch(%s) error field = %q, want %q", tt.body, pe.param, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMergePatch(%s): %v", tt.body, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMergePatch(%s) = %s, want %s", tt.body, formatUpdate(got), formatUpdate(tt.want))
+			}
+		})
+	}
+}
+
+// formatUpdate выводит значения полей SongUpdate вместо указателей.
+func formatUpdate(upd repository.SongUpdate) string {
+	var parts []string
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"group", upd.Group},
+		{"title", upd.Title},
+		{"release_date", upd.ReleaseDate},
+		{"lyrics", upd.Lyrics},
+		{"link", upd.Link},
+	} {
+		if f.value != nil {
+			parts = append(parts, f.name+"="+*f.value)
+		}
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,460 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// headerUserID - заголовок с идентификатором пользователя. Сервис не проверяет
+	// подлинность пользователя: заголовок должен устанавливать шлюз перед сервисом.
+	headerUserID = "X-User-ID"
+	// maxUserIDLen - максимальная длина идентификатора пользователя (по размеру столбца в базе данных).
+	maxUserIDLen = 100
+	// maxPlaylistDescriptionLen - максимальная длина описания плейлиста.
+	maxPlaylistDescriptionLen = 1000
+)
+
+var errUserRequired = errors.New("user id required")
+
+type PlaylistListResponse struct {
+	Items      []domain.Playlist `json:"items"`
+	Page       int               `json:"page" example:"1"`
+	Limit      int               `json:"limit" example:"10"`
+	Total      int               `json:"total" example:"42"`
+	TotalPages int               `json:"total_pages" example:"5"`
+}
+
+type PlaylistRequest struct {
+	Name        string `json:"name" example:"В дорогу"`
+	Description string `json:"description" example:"Песни для долгой поездки"`
+	Public      bool   `json:"public" example:"false"`
+}
+
+type PlaylistEntryRequest struct {
+	SongID string `json:"song_id" example:"1"`
+	// Позиция новой записи, начиная с 1; по умолчанию - в конец плейлиста
+	Position int `json:"position" example:"1"`
+}
+
+type MoveEntryRequest struct {
+	// Новая позиция записи, начиная с 1
+	Position int `json:"position" example:"1"`
+}
+
+type DuplicatePlaylistRequest struct {
+	// Название копии; по умолчанию - название исходного плейлиста
+	Name string `json:"name" example:"В дорогу (копия)"`
+}
+
+// GetPlaylists возвращает список плейлистов.
+// @Summary Получить список плейлистов
+// @Description Возвращает публичные плейлисты и плейлисты пользователя из заголовка X-User-ID,
+// @Description начиная с недавно изменённых.
+// @Tags playlists
+// @Produce  json
+// @Param X-User-ID header string false "Идентификатор пользователя"
+// @Param owner query string false "Владелец плейлистов"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество записей на странице" default(10) maximum(100)
+// @Success 200 {object} PlaylistListResponse "Страница списка плейлистов"
+// @Failure 400 {object} FieldErrorResponse "Некорректный номер страницы"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists [get]
+func (h *Handlers) GetPlaylists(c echo.Context) error {
+	h.logger.Info("GetPlaylists called")
+
+	filter := repository.PlaylistFilter{
+		Viewer: userID(c),
+		Owner:  strings.TrimSpace(c.QueryParam("owner")),
+	}
+	page, limit, err := pageParams(c)
+	if err != nil {
+		h.logger.Warn("Invalid page param", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	list, err := h.playlists.GetPlaylists(filter, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get playlists", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+	}
+	return c.JSON(http.StatusOK, PlaylistListResponse{
+		Items:      list.Items,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		Total:      list.Total,
+		TotalPages: (list.Total + list.Limit - 1) / list.Limit,
+	})
+}
+
+// GetPlaylist возвращает плейлист с записями.
+// @Summary Получить плейлист
+// @Description Возвращает публичный плейлист или плейлист пользователя с записями в порядке позиций.
+// @Tags playlists
+// @Produce  json
+// @Param X-User-ID header string false "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} domain.Playlist "Плейлист"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID плейлиста"
+// @Failure 404 {object} ErrorResponse "Плейлист не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [get]
+func (h *Handlers) GetPlaylist(c echo.Context) error {
+	h.logger.Info("GetPlaylist called", slog.String("playlist_id", c.Param("id")))
+	id, err := playlistIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	playlist, err := h.playlists.GetPlaylist(id, userID(c))
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, playlist)
+}
+
+// AddPlaylist создаёт плейлист.
+// @Summary Создать плейлист
+// @Description Создаёт пустой плейлист пользователя из заголовка X-User-ID.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param playlist body PlaylistRequest true "Плейлист"
+// @Success 201 {object} domain.Playlist "Плейлист создан"
+// @Header 201 {string} Location "Адрес созданного плейлиста: /playlists/{id}"
+// @Failure 400 {object} FieldErrorResponse "Некорректные данные плейлиста"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists [post]
+func (h *Handlers) AddPlaylist(c echo.Context) error {
+	h.logger.Info("AddPlaylist called")
+	user, err := requireUser(c)
+	if err != nil {
+		return h.playlistError(c, "", err)
+	}
+	in, err := playlistInputFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	playlist, err := h.playlists.AddPlaylist(user, in)
+	if err != nil {
+		return h.playlistError(c, "", err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, "/playlists/"+playlist.ID)
+	return c.JSON(http.StatusCreated, playlist)
+}
+
+// UpdatePlaylist изменяет плейлист.
+// @Summary Изменить плейлист
+// @Description Заменяет название, описание и видимость плейлиста; записи не изменяются.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param playlist body PlaylistRequest true "Новые данные плейлиста"
+// @Success 200 {object} domain.Playlist "Плейлист изменён"
+// @Failure 400 {object} FieldErrorResponse "Некорректные данные плейлиста"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 403 {object} ErrorResponse "Плейлист принадлежит другому пользователю"
+// @Failure 404 {object} ErrorResponse "Плейлист не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [put]
+func (h *Handlers) UpdatePlaylist(c echo.Context) error {
+	h.logger.Info("UpdatePlaylist called", slog.String("playlist_id", c.Param("id")))
+	id, user, err := h.playlistTarget(c)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	in, err := playlistInputFromBody(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	playlist, err := h.playlists.UpdatePlaylist(id, user, in)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, playlist)
+}
+
+// DeletePlaylist удаляет плейлист.
+// @Summary Удалить плейлист
+// @Description Удаляет плейлист с записями; песни остаются в библиотеке.
+// @Tags playlists
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} SuccessResponse "Плейлист удалён"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID плейлиста"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 403 {object} ErrorResponse "Плейлист принадлежит другому пользователю"
+// @Failure 404 {object} ErrorResponse "Плейлист не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [delete]
+func (h *Handlers) DeletePlaylist(c echo.Context) error {
+	h.logger.Info("DeletePlaylist called", slog.String("playlist_id", c.Param("id")))
+	id, user, err := h.playlistTarget(c)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+
+	if err := h.playlists.DeletePlaylist(id, user); err != nil {
+		return h.playlistError(c, id, err)
+	}
+	h.logger.Info("Playlist deleted successfully", slog.String("playlist_id", id))
+	return c.JSON(http.StatusOK, SuccessResponse{"Плейлист удалён"})
+}
+
+// AddPlaylistEntry добавляет песню в плейлист.
+// @Summary Добавить песню в плейлист
+// @Description Добавляет песню на указанную позицию (по умолчанию - в конец); записи с этой позиции
+// @Description сдвигаются вниз. Одна песня может входить в плейлист несколько раз.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param entry body PlaylistEntryRequest true "Песня и позиция"
+// @Success 200 {object} domain.Playlist "Плейлист с новой записью"
+// @Failure 400 {object} FieldErrorResponse "Некорректная позиция или песня не найдена"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 403 {object} ErrorResponse "Плейлист принадлежит другому пользователю"
+// @Failure 404 {object} ErrorResponse "Плейлист не найден"
+// @Failure 409 {object} ErrorResponse "В плейлисте максимальное количество записей"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries [post]
+func (h *Handlers) AddPlaylistEntry(c echo.Context) error {
+	h.logger.Info("AddPlaylistEntry called", slog.String("playlist_id", c.Param("id")))
+	id, user, err := h.playlistTarget(c)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	var req PlaylistEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные записи"})
+	}
+	songID, ok := parseID(strings.TrimSpace(req.SongID))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "ID песни должен быть положительным целым числом", Field: "song_id"})
+	}
+	if req.Position < 0 {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Позиция должна быть положительной", Field: "position"})
+	}
+
+	playlist, err := h.playlists.AddEntry(id, user, songID, req.Position)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, playlist)
+}
+
+// RemovePlaylistEntry удаляет запись из плейлиста.
+// @Summary Удалить запись из плейлиста
+// @Description Удаляет запись; следующие записи сдвигаются вверх.
+// @Tags playlists
+// @Produce  json
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param entry_id path int true "ID записи плейлиста"
+// @Success 200 {object} domain.Playlist "Плейлист без записи"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 403 {object} ErrorResponse "Плейлист принадлежит другому пользователю"
+// @Failure 404 {object} ErrorResponse "Плейлист или запись не найдены"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries/{entry_id} [delete]
+func (h *Handlers) RemovePlaylistEntry(c echo.Context) error {
+	h.logger.Info("RemovePlaylistEntry called",
+		slog.String("playlist_id", c.Param("id")), slog.String("entry_id", c.Param("entry_id")))
+	id, user, err := h.playlistTarget(c)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	entryID, err := entryIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	playlist, err := h.playlists.RemoveEntry(id, user, entryID)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, playlist)
+}
+
+// MovePlaylistEntry переносит запись плейлиста на другую позицию.
+// @Summary Переместить запись плейлиста
+// @Description Переносит запись на указанную позицию; записи между старой и новой позицией сдвигаются.
+// @Description Одновременные изменения одного плейлиста выполняются по очереди.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param entry_id path int true "ID записи плейлиста"
+// @Param move body MoveEntryRequest true "Новая позиция"
+// @Success 200 {object} domain.Playlist "Плейлист с новым порядком записей"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID или позиция"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 403 {object} ErrorResponse "Плейлист принадлежит другому пользователю"
+// @Failure 404 {object} ErrorResponse "Плейлист или запись не найдены"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries/{entry_id}/move [post]
+func (h *Handlers) MovePlaylistEntry(c echo.Context) error {
+	h.logger.Info("MovePlaylistEntry called",
+		slog.String("playlist_id", c.Param("id")), slog.String("entry_id", c.Param("entry_id")))
+	id, user, err := h.playlistTarget(c)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	entryID, err := entryIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	var req MoveEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные перемещения"})
+	}
+	if req.Position < 1 {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Позиция должна быть положительной", Field: "position"})
+	}
+
+	playlist, err := h.playlists.MoveEntry(id, user, entryID, req.Position)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, playlist)
+}
+
+// DuplicatePlaylist копирует плейлист.
+// @Summary Скопировать плейлист
+// @Description Копирует свой или публичный плейлист со всеми записями в новый приватный плейлист
+// @Description пользователя из заголовка X-User-ID.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param X-User-ID header string true "Идентификатор пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param playlist body DuplicatePlaylistRequest false "Название копии"
+// @Success 201 {object} domain.Playlist "Копия плейлиста"
+// @Header 201 {string} Location "Адрес копии: /playlists/{id}"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID или название"
+// @Failure 401 {object} ErrorResponse "Не задан заголовок X-User-ID"
+// @Failure 404 {object} ErrorResponse "Плейлист не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/duplicate [post]
+func (h *Handlers) DuplicatePlaylist(c echo.Context) error {
+	h.logger.Info("DuplicatePlaylist called", slog.String("playlist_id", c.Param("id")))
+	id, user, err := h.playlistTarget(c)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	var req DuplicatePlaylistRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные плейлиста"})
+	}
+	name := strings.TrimSpace(req.Name)
+	if utf8.RuneCountInString(name) > maxSongNameLen {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{
+			Message: fmt.Sprintf("Не более %d символов", maxSongNameLen), Field: "name",
+		})
+	}
+
+	playlist, err := h.playlists.DuplicatePlaylist(id, user, name)
+	if err != nil {
+		return h.playlistError(c, id, err)
+	}
+	h.logger.Info("Playlist duplicated", slog.String("playlist_id", id), slog.String("copy_id", playlist.ID))
+	c.Response().Header().Set(echo.HeaderLocation, "/playlists/"+playlist.ID)
+	return c.JSON(http.StatusCreated, playlist)
+}
+
+// userID возвращает идентификатор пользователя из заголовка запроса
+// или пустую строку, если он не задан.
+func userID(c echo.Context) string {
+	return strings.TrimSpace(c.Request().Header.Get(headerUserID))
+}
+
+// requireUser возвращает идентификатор пользователя, без которого
+// нельзя изменять плейлисты.
+func requireUser(c echo.Context) (string, error) {
+	user := userID(c)
+	if user == "" {
+		return "", errUserRequired
+	}
+	if utf8.RuneCountInString(user) > maxUserIDLen {
+		return "", &paramError{headerUserID, fmt.Sprintf("Не более %d символов", maxUserIDLen)}
+	}
+	return user, nil
+}
+
+// playlistTarget возвращает ID плейлиста из пути и пользователя, изменяющего плейлист.
+func (h *Handlers) playlistTarget(c echo.Context) (id, user string, err error) {
+	id, err = playlistIDParam(c)
+	if err != nil {
+		return "", "", err
+	}
+	user, err = requireUser(c)
+	return id, user, err
+}
+
+// playlistInputFromBody разбирает и проверяет данные плейлиста из тела запроса.
+func playlistInputFromBody(c echo.Context) (repository.PlaylistInput, error) {
+	var req PlaylistRequest
+	if err := c.Bind(&req); err != nil {
+		return repository.PlaylistInput{}, &paramError{"", "Некорректные данные плейлиста"}
+	}
+	in := repository.PlaylistInput{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Public:      req.Public,
+	}
+	if in.Name == "" {
+		return in, &paramError{"name", "Поле обязательно"}
+	}
+	if utf8.RuneCountInString(in.Name) > maxSongNameLen {
+		return in, &paramError{"name", fmt.Sprintf("Не более %d символов", maxSongNameLen)}
+	}
+	if utf8.RuneCountInString(in.Description) > maxPlaylistDescriptionLen {
+		return in, &paramError{"description", fmt.Sprintf("Не более %d символов", maxPlaylistDescriptionLen)}
+	}
+	return in, nil
+}
+
+// playlistError формирует ответ на ошибку работы с плейлистом.
+func (h *Handlers) playlistError(c echo.Context, id string, err error) error {
+	var pe *paramError
+	switch {
+	case errors.As(err, &pe):
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	case errors.Is(err, errUserRequired):
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{"Требуется заголовок " + headerUserID})
+	case errors.Is(err, repository.ErrPlaylistNotFound):
+		h.logger.Warn("Playlist not found", slog.String("playlist_id", id))
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Плейлист не найден"})
+	case errors.Is(err, repository.ErrPlaylistForbidden):
+		return c.JSON(http.StatusForbidden, ErrorResponse{"Плейлист принадлежит другому пользователю"})
+	case errors.Is(err, repository.ErrEntryNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Запись плейлиста не найдена"})
+	case errors.Is(err, repository.ErrInvalidPosition):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Позиция за пределами плейлиста", Field: "position"})
+	case errors.Is(err, repository.ErrPlaylistFull):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			fmt.Sprintf("В плейлисте не может быть больше %d записей", repository.MaxPlaylistEntries),
+		})
+	case errors.Is(err, repository.ErrNotFound):
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Песня не найдена", Field: "song_id"})
+	}
+	h.logger.Error("Playlist request failed", slog.String("playlist_id", id), slog.Any("error", err))
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		want      string
+		wantErr   error
+		wantField string
+	}{
+		{name: "user", header: "alice", want: "alice"},
+		{name: "spaces are trimmed", header: "  alice ", want: "alice"},
+		{name: "longest id", header: strings.Repeat("ю", maxUserIDLen), want: strings.Repeat("ю", maxUserIDLen)},
+		{name: "no header", wantErr: errUserRequired},
+		{name: "only spaces", header: "   ", wantErr: errUserRequired},
+		{name: "too long", header: strings.Repeat("ю", maxUserIDLen+1), wantField: headerUserID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/playlists", nil)
+			if tt.header != "" {
+				req.Header.Set(headerUserID, tt.header)
+			}
+			got, err := requireUser(echo.New().NewContext(req, httptest.NewRecorder()))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("requireUser() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantField != "":
+				var pe *paramError
+				if !errors.As(err, &pe) || pe.param != tt.wantField {
+					t.Errorf("requireUser() error = %v, want *paramError for %s", err, tt.wantField)
+				}
+			case err != nil:
+				t.Errorf("requireUser(): %v", err)
+			case got != tt.want:
+				t.Errorf("requireUser() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistInputFromBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      repository.PlaylistInput
+		wantErr   bool
+		wantField string
+	}{
+		{
+			name: "playlist",
+			body: `{"name": "В дорогу", "description": "Песни для долгой поездки", "public": true}`,
+			want: repository.PlaylistInput{Name: "В дорогу", Description: "Песни для долгой поездки", Public: true},
+		},
+		{
+			name: "spaces are trimmed",
+			body: `{"name": "  В дорогу ", "description": " \n"}`,
+			want: repository.PlaylistInput{Name: "В дорогу"},
+		},
+		{
+			name: "longest name and description",
+			body: `{"name": "` + strings.Repeat("я", maxSongNameLen) + `", "description": "` + strings.Repeat("я", maxPlaylistDescriptionLen) + `"}`,
+			want: repository.PlaylistInput{Name: strings.Repeat("я", maxSongNameLen), Description: strings.Repeat("я", maxPlaylistDescriptionLen)},
+		},
+		{name: "no name", body: `{"description": "Песни"}`, wantErr: true, wantField: "name"},
+		{name: "blank name", body: `{"name": "   "}`, wantErr: true, wantField: "name"},
+		{name: "long name", body: `{"name": "` + strings.Repeat("я", maxSongNameLen+1) + `"}`, wantErr: true, wantField: "name"},
+		{
+			name:      "long description",
+			body:      `{"name": "В дорогу", "description": "` + strings.Repeat("я", maxPlaylistDescriptionLen+1) + `"}`,
+			wantErr:   true,
+			wantField: "description",
+		},
+		{name: "invalid json", body: `{"name": `, wantErr: true},
+		{name: "wrong type", body: `{"name": "В дорогу", "public": "yes"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/playlists", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			got, err := playlistInputFromBody(echo.New().NewContext(req, httptest.NewRecorder()))
+			if tt.wantErr {
+				var pe *paramError
+				if !errors.As(err, &pe) {
+					t.Fatalf("playlistInputFromBody(%s) error = %v, want *paramError", tt.body, err)
+				}
+				if pe.param != tt.wantField {
+					t.Errorf("playlistInputFromBody(%s) error field = %q, want %q", tt.body, pe.param, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("playlistInputFromBody(%s): %v", tt.body, err)
+			}
+			if got != tt.want {
+				t.Errorf("playlistInputFromBody(%s) = %+v, want %+v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,17 +4,60 @@ import (
 	"github.com/labstack/echo/v4"
 	"log/slog"
 	"music-test-lib/config"
-	"music-test-lib/internal/service"
 )
 
-// RegisterRoutes регистрирует маршруты для работы с API песен.
-func RegisterRoutes(e *echo.Echo, logger *slog.Logger, service *service.SongService, cfg *config.Config) {
-	handlers := NewHandlers(logger, service, cfg)
+// RegisterRoutes регистрирует маршруты для работы с API песен, каталога (исполнители, альбомы, жанры, теги) и плейлистов.
+func RegisterRoutes(e *echo.Echo, logger *slog.Logger, services Services, cfg *config.Config) {
+	handlers := NewHandlers(logger, services, cfg)
 
 	// REST методы для библиотеки песен
 	e.GET("/songs", handlers.GetSongs)          // Получение списка песен с фильтрацией и пагинацией
-	e.GET("/songs/:id", handlers.GetSongText)   // Получение текста песни с пагинацией по куплетам
+	e.GET("/songs/:id", handlers.GetSong)       // Получение песни
 	e.POST("/songs", handlers.AddSong)          // Добавление новой песни
-	e.PUT("/songs/:id", handlers.UpdateSong)    // Изменение данных песни
+	e.PUT("/songs/:id", handlers.UpdateSong)    // Замена данных песни
+	e.PATCH("/songs/:id", handlers.PatchSong)   // Изменение отдельных полей песни
 	e.DELETE("/songs/:id", handlers.DeleteSong) // Удаление песни
+	e.DELETE("/songs", handlers.DeleteSongs)    // Массовое удаление песен
+
+	e.GET("/songs/:id/lyrics", handlers.GetSongLyrics)   // Получение текста песни с пагинацией по куплетам
+	e.GET("/songs/:id/verses", handlers.GetSongVerses)   // Текст песни, разобранный на строфы
+	e.POST("/songs/:id/refresh", handlers.RefreshSong)   // Повторное получение данных песни из внешнего API
+	e.PUT("/songs/:id/credits", handlers.SetSongCredits) // Замена списка участников песни
+	e.PUT("/songs/:id/genres", handlers.SetSongGenres)   // Замена жанров песни
+	e.PUT("/songs/:id/tags", handlers.SetSongTags)       // Замена тегов песни
+
+	e.GET("/artists", handlers.GetArtists)               // Список исполнителей
+	e.GET("/artists/:id", handlers.GetArtist)            // Получение исполнителя
+	e.POST("/artists", handlers.AddArtist)               // Добавление исполнителя
+	e.PUT("/artists/:id", handlers.RenameArtist)         // Переименование исполнителя
+	e.DELETE("/artists/:id", handlers.DeleteArtist)      // Удаление исполнителя без песен и альбомов
+	e.GET("/artists/:id/songs", handlers.GetArtistSongs) // Песни исполнителя
+
+	e.GET("/albums", handlers.GetAlbums)                 // Список альбомов
+	e.GET("/albums/:id", handlers.GetAlbum)              // Получение альбома с треками
+	e.POST("/albums", handlers.AddAlbum)                 // Добавление альбома
+	e.PUT("/albums/:id", handlers.UpdateAlbum)           // Замена данных альбома
+	e.PUT("/albums/:id/tracks", handlers.SetAlbumTracks) // Замена списка треков альбома
+	e.DELETE("/albums/:id", handlers.DeleteAlbum)        // Удаление альбома
+
+	e.GET("/genres", handlers.GetGenres)          // Дерево жанров
+	e.GET("/genres/:id", handlers.GetGenre)       // Получение жанра с поджанрами
+	e.POST("/genres", handlers.AddGenre)          // Добавление жанра
+	e.PUT("/genres/:id", handlers.UpdateGenre)    // Изменение или перенос жанра
+	e.DELETE("/genres/:id", handlers.DeleteGenre) // Удаление жанра без поджанров
+
+	e.GET("/tags", handlers.GetTags)          // Список тегов
+	e.POST("/tags", handlers.AddTag)          // Добавление тега
+	e.PUT("/tags/:id", handlers.RenameTag)    // Переименование тега
+	e.DELETE("/tags/:id", handlers.DeleteTag) // Удаление тега
+
+	e.GET("/playlists", handlers.GetPlaylists)                                  // Список доступных плейлистов
+	e.GET("/playlists/:id", handlers.GetPlaylist)                               // Получение плейлиста с записями
+	e.POST("/playlists", handlers.AddPlaylist)                                  // Создание плейлиста
+	e.PUT("/playlists/:id", handlers.UpdatePlaylist)                            // Изменение плейлиста
+	e.DELETE("/playlists/:id", handlers.DeletePlaylist)                         // Удаление плейлиста
+	e.POST("/playlists/:id/entries", handlers.AddPlaylistEntry)                 // Добавление песни в плейлист
+	e.DELETE("/playlists/:id/entries/:entry_id", handlers.RemovePlaylistEntry)  // Удаление записи плейлиста
+	e.POST("/playlists/:id/entries/:entry_id/move", handlers.MovePlaylistEntry) // Перемещение записи плейлиста
+	e.POST("/playlists/:id/duplicate", handlers.DuplicatePlaylist)              // Копирование плейлиста
 }
@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTagNameLen - максимальная длина названия тега (по размеру столбца в базе данных).
+	maxTagNameLen = 50
+	// maxSongTags - максимальное количество тегов одной песни.
+	maxSongTags = 50
+)
+
+type TagListResponse struct {
+	Items      []domain.Tag `json:"items"`
+	Page       int          `json:"page" example:"1"`
+	Limit      int          `json:"limit" example:"10"`
+	Total      int          `json:"total" example:"42"`
+	TotalPages int          `json:"total_pages" example:"5"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" example:"live"`
+}
+
+type SongTagsRequest struct {
+	Tags []string `json:"tags" example:"live,acoustic"`
+}
+
+// GetTags возвращает список тегов.
+// @Summary Получить список тегов
+// @Description Возвращает теги в алфавитном порядке с количеством песен.
+// @Tags tags
+// @Produce  json
+// @Param name query string false "Часть названия тега"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество записей на странице" default(10) maximum(100)
+// @Success 200 {object} TagListResponse "Страница списка тегов"
+// @Failure 400 {object} FieldErrorResponse "Некорректный номер страницы"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /tags [get]
+func (h *Handlers) GetTags(c echo.Context) error {
+	h.logger.Info("GetTags called")
+
+	page, limit, err := pageParams(c)
+	if err != nil {
+		h.logger.Warn("Invalid page param", slog.Any("error", err))
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	list, err := h.tags.GetTags(strings.TrimSpace(c.QueryParam("name")), page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get tags", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+	}
+	return c.JSON(http.StatusOK, TagListResponse{
+		Items:      list.Items,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		Total:      list.Total,
+		TotalPages: (list.Total + list.Limit - 1) / list.Limit,
+	})
+}
+
+// AddTag добавляет тег.
+// @Summary Добавить тег
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param tag body TagRequest true "Тег"
+// @Success 201 {object} domain.Tag "Тег добавлен"
+// @Failure 400 {object} FieldErrorResponse "Некорректное название тега"
+// @Failure 409 {object} ErrorResponse "Тег с таким названием уже существует"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /tags [post]
+func (h *Handlers) AddTag(c echo.Context) error {
+	h.logger.Info("AddTag called")
+	var req TagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные тега"})
+	}
+	name, err := tagName(req.Name, "name")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	tag, err := h.tags.AddTag(name)
+	if err != nil {
+		return h.tagError(c, "", err)
+	}
+	return c.JSON(http.StatusCreated, tag)
+}
+
+// RenameTag переименовывает тег.
+// @Summary Переименовать тег
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID тега"
+// @Param tag body TagRequest true "Новое название тега"
+// @Success 200 {object} domain.Tag "Тег переименован"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID или название тега"
+// @Failure 404 {object} ErrorResponse "Тег не найден"
+// @Failure 409 {object} ErrorResponse "Тег с таким названием уже существует"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /tags/{id} [put]
+func (h *Handlers) RenameTag(c echo.Context) error {
+	h.logger.Info("RenameTag called", slog.String("tag_id", c.Param("id")))
+	id, err := tagIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	var req TagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные тега"})
+	}
+	name, err := tagName(req.Name, "name")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	tag, err := h.tags.RenameTag(id, name)
+	if err != nil {
+		return h.tagError(c, id, err)
+	}
+	return c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag удаляет тег.
+// @Summary Удалить тег
+// @Description Удаляет тег; у песен этот тег снимается.
+// @Tags tags
+// @Param id path int true "ID тега"
+// @Success 200 {object} SuccessResponse "Тег удалён"
+// @Failure 400 {object} FieldErrorResponse "Некорректный ID тега"
+// @Failure 404 {object} ErrorResponse "Тег не найден"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /tags/{id} [delete]
+func (h *Handlers) DeleteTag(c echo.Context) error {
+	h.logger.Info("DeleteTag called", slog.String("tag_id", c.Param("id")))
+	id, err := tagIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+
+	if err := h.tags.DeleteTag(id); err != nil {
+		return h.tagError(c, id, err)
+	}
+	h.logger.Info("Tag deleted successfully", slog.String("tag_id", id))
+	return c.JSON(http.StatusOK, SuccessResponse{"Тег удалён"})
+}
+
+// SetSongTags заменяет теги песни.
+// @Summary Задать теги песни
+// @Description Заменяет теги песни. Теги сравниваются без учёта регистра; отсутствующие теги создаются.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID песни"
+// @Param If-Match header string false "ETag песни из GET /songs/{id}"
+// @Param tags body SongTagsRequest true "Теги песни"
+// @Success 200 {object} domain.Song "Песня с новыми тегами"
+// @Header 200 {string} ETag "Версия песни"
+// @Failure 400 {object} FieldErrorResponse "Некорректные теги"
+// @Failure 404 {object} ErrorResponse "Песня не найдена"
+// @Failure 412 {object} ErrorResponse "Песня изменена после получения ETag"
+// @Failure 428 {object} ErrorResponse "Требуется заголовок If-Match"
+// @Failure 500 {object} ErrorResponse "Внутренняя ошибка сервера"
+// @Router /songs/{id}/tags [put]
+func (h *Handlers) SetSongTags(c echo.Context) error {
+	h.logger.Info("SetSongTags called", slog.String("song_id", c.Param("id")))
+	id, err := songIDParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+	}
+	versions, err := ifMatchVersions(c, h.cfg.HTTPServer.RequireIfMatch)
+	if handled, resp := preconditionError(c, err); handled {
+		return resp
+	}
+
+	var req SongTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{Message: "Некорректные данные тегов"})
+	}
+	if len(req.Tags) > maxSongTags {
+		return c.JSON(http.StatusBadRequest, FieldErrorResponse{
+			Message: fmt.Sprintf("Не более %d тегов", maxSongTags), Field: "tags",
+		})
+	}
+	var tags []string
+	seen := map[string]bool{}
+	for i, value := range req.Tags {
+		name, err := tagName(value, fmt.Sprintf("tags[%d]", i))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, newFieldErrorResponse(err))
+		}
+		if key := strings.ToLower(strings.Join(strings.Fields(name), " ")); !seen[key] {
+			seen[key] = true
+			tags = append(tags, name)
+		}
+	}
+
+	song, err := h.service.SetSongTags(id, tags, versions)
+	if err != nil {
+		return h.updateSongError(c, id, err)
+	}
+	c.Response().Header().Set("ETag", songETag(song))
+	return c.JSON(http.StatusOK, song)
+}
+
+// tagName проверяет название тега и убирает пробелы по краям.
+// Запятая в названии запрещена: она разделяет теги в фильтре GET /songs.
+func tagName(name, param string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", &paramError{param, "Название тега не может быть пустым"}
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLen {
+		return "", &paramError{param, fmt.Sprintf("Не более %d символов", maxTagNameLen)}
+	}
+	if strings.Contains(name, ",") {
+		return "", &paramError{param, "Название тега не может содержать запятую"}
+	}
+	return name, nil
+}
+
+// tagError формирует ответ на ошибку работы с тегом.
+func (h *Handlers) tagError(c echo.Context, id string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		h.logger.Warn("Tag not found", slog.String("tag_id", id))
+		return c.JSON(http.StatusNotFound, ErrorResponse{"Тег не найден"})
+	case errors.Is(err, repository.ErrTagExists):
+		return c.JSON(http.StatusConflict, ErrorResponse{"Тег с таким названием уже существует"})
+	}
+	h.logger.Error("Tag request failed", slog.String("tag_id", id), slog.Any("error", err))
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{"Внутренняя ошибка сервера"})
+}
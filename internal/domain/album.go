@@ -0,0 +1,26 @@
+package domain
+
+// Album представляет альбом исполнителя.
+// @Description Альбом.
+type Album struct {
+	ID          string `json:"id" example:"1"`
+	Title       string `json:"title" example:"Black Holes and Revelations"`
+	ArtistID    string `json:"artist_id" example:"1"`
+	Artist      string `json:"artist" example:"Muse"`
+	ReleaseDate string `json:"release_date" example:"2006-07-03"`
+	CoverURL    string `json:"cover_url" example:"https://example.com/covers/bhar.jpg"`
+	// Количество треков альбома
+	TrackCount int `json:"track_count" example:"11"`
+	// Треки в порядке дисков и номеров; заполняются только для одного альбома
+	Tracks []AlbumTrack `json:"tracks,omitempty"`
+}
+
+// AlbumTrack - песня на определённой позиции альбома.
+// @Description Трек альбома.
+type AlbumTrack struct {
+	DiscNumber  int    `json:"disc_number" example:"1"`
+	TrackNumber int    `json:"track_number" example:"3"`
+	SongID      string `json:"song_id" example:"1"`
+	Group       string `json:"group" example:"Muse"`
+	Title       string `json:"title" example:"Supermassive Black Hole"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+// Artist представляет исполнителя (группу).
+// @Description Исполнитель.
+type Artist struct {
+	ID   string `json:"id" example:"1"`
+	Name string `json:"name" example:"Muse"`
+	// Количество песен исполнителя в библиотеке
+	SongCount int `json:"song_count" example:"12"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+// CreditRole - роль исполнителя в песне.
+type CreditRole string
+
+const (
+	// CreditPrimary - основной исполнитель.
+	CreditPrimary CreditRole = "primary"
+	// CreditFeatured - приглашённый исполнитель (feat.).
+	CreditFeatured CreditRole = "featured"
+	// CreditComposer - композитор.
+	CreditComposer CreditRole = "composer"
+	// CreditLyricist - автор текста.
+	CreditLyricist CreditRole = "lyricist"
+	// CreditProducer - продюсер.
+	CreditProducer CreditRole = "producer"
+)
+
+// CreditRoles - допустимые роли в порядке их вывода в списке участников.
+var CreditRoles = []CreditRole{CreditPrimary, CreditFeatured, CreditComposer, CreditLyricist, CreditProducer}
+
+// Valid сообщает, является ли r допустимой ролью.
+func (r CreditRole) Valid() bool {
+	for _, role := range CreditRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Credit - участие исполнителя в песне.
+// @Description Участник песни.
+type Credit struct {
+	ArtistID string     `json:"artist_id" example:"2"`
+	Artist   string     `json:"artist" example:"Rihanna"`
+	Role     CreditRole `json:"role" example:"featured" enums:"primary,featured,composer,lyricist,producer"`
+}
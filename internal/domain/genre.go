@@ -0,0 +1,29 @@
+package domain
+
+// Genre представляет жанр. Жанры образуют дерево: у поджанра задан родительский жанр.
+// @Description Жанр.
+type Genre struct {
+	ID   string `json:"id" example:"2"`
+	Name string `json:"name" example:"Alternative Rock"`
+	// ID родительского жанра; пуст у жанров верхнего уровня
+	ParentID string `json:"parent_id,omitempty" example:"1"`
+	// Количество песен с этим жанром (без учёта поджанров)
+	SongCount int `json:"song_count" example:"12"`
+	// Поджанры
+	Subgenres []Genre `json:"subgenres,omitempty"`
+}
+
+// SongGenre - жанр в данных песни.
+type SongGenre struct {
+	ID   string `json:"id" example:"2"`
+	Name string `json:"name" example:"Alternative Rock"`
+}
+
+// Tag представляет произвольный тег песен.
+// @Description Тег.
+type Tag struct {
+	ID   string `json:"id" example:"1"`
+	Name string `json:"name" example:"live"`
+	// Количество песен с этим тегом
+	SongCount int `json:"song_count" example:"5"`
+}
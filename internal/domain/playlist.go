@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Playlist представляет плейлист пользователя.
+// @Description Плейлист.
+type Playlist struct {
+	ID          string `json:"id" example:"1"`
+	Name        string `json:"name" example:"В дорогу"`
+	Description string `json:"description" example:"Песни для долгой поездки"`
+	// Идентификатор пользователя-владельца
+	Owner string `json:"owner" example:"user-42"`
+	// Виден ли плейлист другим пользователям
+	Public bool `json:"public" example:"false"`
+	// Количество записей в плейлисте
+	EntryCount int       `json:"entry_count" example:"12"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-05-01T12:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-05-01T12:00:00Z"`
+	// Записи в порядке воспроизведения; заполняются только для одного плейлиста
+	Entries []PlaylistEntry `json:"entries,omitempty"`
+}
+
+// PlaylistEntry - песня на определённой позиции плейлиста.
+// Одна песня может входить в плейлист несколько раз.
+// @Description Запись плейлиста.
+type PlaylistEntry struct {
+	ID string `json:"id" example:"7"`
+	// Позиция записи в плейлисте, начиная с 1
+	Position int       `json:"position" example:"1"`
+	SongID   string    `json:"song_id" example:"1"`
+	Group    string    `json:"group" example:"Muse"`
+	Title    string    `json:"title" example:"Supermassive Black Hole"`
+	AddedAt  time.Time `json:"added_at" example:"2024-05-01T12:00:00Z"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // Song представляет структуру песни.
 // @Description Модель данных песни.
 //
@@ -9,7 +11,8 @@ package domain
 //	  "title": "Supermassive Black Hole",
 //	  "release_date": "2006-07-16",
 //	  "lyrics": "Ooh baby, don't you know I suffer? ...",
-//	  "link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+//	  "link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+//	  "enrichment_status": "ready"
 //	}
 type Song struct {
 	ID          string `json:"id" example:"1"`
@@ -18,8 +21,52 @@ type Song struct {
 	ReleaseDate string `json:"release_date" example:"2006-07-16"`
 	Lyrics      string `json:"lyrics" example:"Ooh baby, don't you know I suffer? ..."`
 	Link        string `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	// ID исполнителя; его название - в поле group
+	ArtistID string `json:"artist_id" example:"1"`
+	// Участники песни; первым идёт основной исполнитель
+	Credits []Credit `json:"credits"`
+	// Жанры песни
+	Genres []SongGenre `json:"genres"`
+	// Теги песни
+	Tags []string `json:"tags" example:"live,acoustic"`
+	// Состояние получения данных о песне из внешнего API
+	EnrichmentStatus EnrichmentStatus `json:"enrichment_status" example:"ready" enums:"pending,ready,failed"`
+	// Последняя ошибка получения данных
+	EnrichmentError string `json:"enrichment_error,omitempty" example:"music info API returned status 503"`
+	// Источник полей, полученных из внешних источников (поле -> имя источника)
+	Sources map[string]string `json:"sources,omitempty"`
+	// Версия данных песни, увеличивается при каждом изменении
+	Version int64 `json:"version" example:"3"`
+	// Время последнего изменения данных песни
+	UpdatedAt time.Time `json:"updated_at" example:"2024-05-01T12:00:00Z"`
+	// Текст песни, разобранный на строфы; заполняется только при запросе строф
+	Stanzas []Stanza `json:"-"`
+}
+
+// ExternalValueApplies сообщает, заменяет ли значение value поля field,
+// полученное из внешнего источника, сохранённое значение current. Пустое
+// значение ничего не заменяет, а заданное вручную (его нет в Sources)
+// заменяется, только если оно пустое.
+func (s *Song) ExternalValueApplies(field, current, value string) bool {
+	if value == "" || value == current {
+		return false
+	}
+	_, external := s.Sources[field]
+	return current == "" || external
 }
 
+// EnrichmentStatus - состояние получения данных о песне из внешнего API.
+type EnrichmentStatus string
+
+const (
+	// EnrichmentPending - данные ещё не получены, запрос будет повторён.
+	EnrichmentPending EnrichmentStatus = "pending"
+	// EnrichmentReady - данные получены.
+	EnrichmentReady EnrichmentStatus = "ready"
+	// EnrichmentFailed - данные получить не удалось.
+	EnrichmentFailed EnrichmentStatus = "failed"
+)
+
 // SongWithoutID представляет структуру песни без ID.
 // @Description Модель данных песни.
 //
@@ -36,4 +83,34 @@ type SongWithoutID struct {
 	ReleaseDate string `json:"release_date" example:"2006-07-16"`
 	Lyrics      string `json:"lyrics" example:"Ooh baby, don't you know I suffer? ..."`
 	Link        string `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	// Источник полей, полученных из внешних источников (поле -> имя источника)
+	Sources map[string]string `json:"sources,omitempty"`
+	// Текст песни, разобранный на строфы
+	Stanzas []Stanza `json:"-"`
+}
+
+// SongListItem представляет песню в списке результатов.
+// Поля Rank и Snippet заполняются только при полнотекстовом поиске,
+// Similarity - только при нечётком поиске.
+// @Description Песня в списке результатов.
+type SongListItem struct {
+	Song
+	Rank       *float64 `json:"rank,omitempty" example:"0.6079"`
+	Snippet    string   `json:"snippet,omitempty" example:"Ooh <mark>baby</mark>, don't you know I suffer?"`
+	Similarity *float64 `json:"similarity,omitempty" example:"0.7"`
+}
+
+// SongSuggestion представляет исправленные значения фильтров,
+// для которых в библиотеке есть похожие песни.
+// @Description Вариант исправления названия группы и/или песни.
+type SongSuggestion struct {
+	Group string `json:"group_name,omitempty" example:"Metallica"`
+	Title string `json:"song_name,omitempty" example:"Nothing Else Matters"`
+}
+
+// FieldChange - изменение поля песни: старое и новое значение.
+type FieldChange struct {
+	Field string `json:"field" example:"link"`
+	Old   string `json:"old" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	New   string `json:"new" example:"https://www.youtube.com/watch?v=nHtxh2ZB8zU"`
 }
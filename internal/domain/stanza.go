@@ -0,0 +1,27 @@
+package domain
+
+// StanzaType - вид строфы текста песни.
+type StanzaType string
+
+const (
+	StanzaVerse     StanzaType = "verse"
+	StanzaChorus    StanzaType = "chorus"
+	StanzaPreChorus StanzaType = "pre_chorus"
+	StanzaBridge    StanzaType = "bridge"
+	StanzaIntro     StanzaType = "intro"
+	StanzaOutro     StanzaType = "outro"
+	StanzaHook      StanzaType = "hook"
+	// StanzaOther - строфа с меткой неизвестного вида.
+	StanzaOther StanzaType = "other"
+)
+
+// Stanza представляет строфу (куплет, припев и т.п.) текста песни.
+// @Description Строфа текста песни.
+type Stanza struct {
+	// Порядковый номер строфы в тексте, начиная с 1
+	Number int        `json:"number" example:"1"`
+	Type   StanzaType `json:"type" example:"chorus" enums:"verse,chorus,pre_chorus,bridge,intro,outro,hook,other"`
+	// Метка строфы из текста, например "Verse 2" для [Verse 2]
+	Label string   `json:"label,omitempty" example:"Chorus"`
+	Lines []string `json:"lines" example:"Ooh,You set my soul alight"`
+}
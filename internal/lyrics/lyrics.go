@@ -0,0 +1,122 @@
+// Package lyrics приводит тексты песен к единому виду и разбирает их на строфы.
+package lyrics
+
+import (
+	"music-test-lib/internal/domain"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// escapedNewline - перевод строки, записанный escape-последовательностью
+// (\n, \r\n, в том числе с повторным экранированием: \\n).
+var escapedNewline = regexp.MustCompile(`(\\+r)?\\+n`)
+
+// Normalize приводит переводы строк к "\n": заменяет escape-последовательности
+// и CRLF, убирает пробелы в конце строк и пустые строки в начале и конце текста.
+func Normalize(text string) string {
+	text = escapedNewline.ReplaceAllString(text, "\n")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Parse разбирает текст песни на строфы. Строфы разделяются пустыми строками
+// или строками-метками вида [Chorus], [Verse 2]. Вид строфы определяется по
+// метке; строфы без метки считаются куплетами.
+func Parse(text string) []domain.Stanza {
+	stanzas := []domain.Stanza{}
+	var current *domain.Stanza
+	flush := func() {
+		if current != nil && len(current.Lines) > 0 {
+			current.Number = len(stanzas) + 1
+			stanzas = append(stanzas, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(Normalize(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			// Метка может быть отделена от строк строфы пустой строкой
+			if current != nil && len(current.Lines) > 0 {
+				flush()
+			}
+			continue
+		}
+		if label, ok := parseLabel(line); ok {
+			flush()
+			current = &domain.Stanza{Type: stanzaType(label), Label: label}
+			continue
+		}
+		if current == nil {
+			current = &domain.Stanza{Type: domain.StanzaVerse}
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	flush()
+	return stanzas
+}
+
+// parseLabel возвращает метку строфы, если строка имеет вид [метка].
+func parseLabel(line string) (string, bool) {
+	if len(line) < 3 || line[0] != '[' || line[len(line)-1] != ']' {
+		return "", false
+	}
+	label := strings.TrimSpace(line[1 : len(line)-1])
+	return label, label != ""
+}
+
+// stanzaTypes - виды строф по первому слову метки без цифр и знаков.
+var stanzaTypes = map[string]domain.StanzaType{
+	"verse":      domain.StanzaVerse,
+	"куплет":     domain.StanzaVerse,
+	"chorus":     domain.StanzaChorus,
+	"refrain":    domain.StanzaChorus,
+	"припев":     domain.StanzaChorus,
+	"prechorus":  domain.StanzaPreChorus,
+	"предприпев": domain.StanzaPreChorus,
+	"bridge":     domain.StanzaBridge,
+	"бридж":      domain.StanzaBridge,
+	"intro":      domain.StanzaIntro,
+	"вступление": domain.StanzaIntro,
+	"outro":      domain.StanzaOutro,
+	"кода":       domain.StanzaOutro,
+	"концовка":   domain.StanzaOutro,
+	"hook":       domain.StanzaHook,
+	"postchorus": domain.StanzaHook,
+	"постприпев": domain.StanzaHook,
+}
+
+// stanzaType определяет вид строфы по метке: "Verse 2: Artist" -> verse,
+// "Pre-Chorus" -> pre_chorus.
+func stanzaType(label string) domain.StanzaType {
+	label, _, _ = strings.Cut(label, ":")
+	fields := strings.Fields(strings.ToLower(label))
+	if len(fields) == 0 {
+		return domain.StanzaOther
+	}
+	// Сначала метка целиком ("pre chorus" -> prechorus), затем первое слово ("chorus x2")
+	if t, ok := stanzaTypes[letters(strings.Join(fields, ""))]; ok {
+		return t
+	}
+	if t, ok := stanzaTypes[letters(fields[0])]; ok {
+		return t
+	}
+	return domain.StanzaOther
+}
+
+// letters возвращает s без символов, не являющихся буквами.
+func letters(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
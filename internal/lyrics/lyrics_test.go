@@ -0,0 +1,119 @@
+package lyrics
+
+import (
+	"music-test-lib/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"unchanged", "Ooh baby\n\nOoh", "Ooh baby\n\nOoh"},
+		{"escaped newlines", `Ooh baby\nOoh\n\nYeah`, "Ooh baby\nOoh\n\nYeah"},
+		{"escaped crlf", `Ooh\r\nYeah`, "Ooh\nYeah"},
+		{"double escaped", `Ooh\\nYeah\\r\\nNo`, "Ooh\nYeah\nNo"},
+		{"crlf and cr", "Ooh\r\nYeah\rNo", "Ooh\nYeah\nNo"},
+		{"trailing spaces", "Ooh  \t\nYeah ", "Ooh\nYeah"},
+		{"leading spaces are kept", "  Ooh\n Yeah", "  Ooh\n Yeah"},
+		{"empty lines at edges", "\n\n  \nOoh\n\n", "Ooh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	verse := func(number int, lines ...string) domain.Stanza {
+		return domain.Stanza{Number: number, Type: domain.StanzaVerse, Lines: lines}
+	}
+	tests := []struct {
+		name string
+		in   string
+		want []domain.Stanza
+	}{
+		{"empty", "", []domain.Stanza{}},
+		{"only blank lines", "\n \n\n", []domain.Stanza{}},
+		{"one verse", "Ooh baby\nOoh", []domain.Stanza{verse(1, "Ooh baby", "Ooh")}},
+		{
+			name: "blank lines separate stanzas",
+			in:   "A\nB\n\n\n C \n",
+			want: []domain.Stanza{verse(1, "A", "B"), verse(2, "C")},
+		},
+		{
+			name: "escaped newlines",
+			in:   `A\nB\n\nC`,
+			want: []domain.Stanza{verse(1, "A", "B"), verse(2, "C")},
+		},
+		{
+			name: "labels",
+			in:   "[Verse 1]\nA\nB\n[Chorus]\nC\n\n[Verse 2: Matt Bellamy]\nD",
+			want: []domain.Stanza{
+				{Number: 1, Type: domain.StanzaVerse, Label: "Verse 1", Lines: []string{"A", "B"}},
+				{Number: 2, Type: domain.StanzaChorus, Label: "Chorus", Lines: []string{"C"}},
+				{Number: 3, Type: domain.StanzaVerse, Label: "Verse 2: Matt Bellamy", Lines: []string{"D"}},
+			},
+		},
+		{
+			name: "label separated by blank line",
+			in:   "[Bridge]\n\nA\nB",
+			want: []domain.Stanza{{Number: 1, Type: domain.StanzaBridge, Label: "Bridge", Lines: []string{"A", "B"}}},
+		},
+		{
+			name: "empty labelled stanza is skipped",
+			in:   "[Intro]\n[Chorus]\nA",
+			want: []domain.Stanza{{Number: 1, Type: domain.StanzaChorus, Label: "Chorus", Lines: []string{"A"}}},
+		},
+		{
+			name: "brackets inside line are not a label",
+			in:   "A [x2]\n[]\n[ ]",
+			want: []domain.Stanza{verse(1, "A [x2]", "[]", "[ ]")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStanzaType(t *testing.T) {
+	tests := []struct {
+		label string
+		want  domain.StanzaType
+	}{
+		{"Verse", domain.StanzaVerse},
+		{"verse 2", domain.StanzaVerse},
+		{"Куплет 1", domain.StanzaVerse},
+		{"Chorus x2", domain.StanzaChorus},
+		{"Refrain", domain.StanzaChorus},
+		{"Припев", domain.StanzaChorus},
+		{"Pre-Chorus", domain.StanzaPreChorus},
+		{"Pre Chorus", domain.StanzaPreChorus},
+		{"Post-Chorus", domain.StanzaHook},
+		{"Bridge: Artist", domain.StanzaBridge},
+		{"Intro", domain.StanzaIntro},
+		{"Outro", domain.StanzaOutro},
+		{"Кода", domain.StanzaOutro},
+		{"Hook", domain.StanzaHook},
+		{"Solo", domain.StanzaOther},
+		{": Artist", domain.StanzaOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			if got := stanzaType(tt.label); got != tt.want {
+				t.Errorf("stanzaType(%q) = %q, want %q", tt.label, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package musicinfo
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker - автомат защиты (circuit breaker) по числу ошибок подряд.
+// После threshold ошибок подряд запросы отклоняются в течение cooldown,
+// затем пропускается один пробный запрос: при успехе автомат закрывается,
+// при ошибке снова размыкается.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow сообщает, можно ли выполнить запрос.
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// success фиксирует успешный запрос.
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+// failure фиксирует неудачный запрос.
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// release фиксирует запрос, результат которого неизвестен (например, отменённый
+// вызывающей стороной): он не учитывается, но освобождает место пробного запроса.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
@@ -0,0 +1,136 @@
+package musicinfo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	// Шаги сценария: запрос (allow) с ожидаемым ответом и его результат
+	// (cancel - запрос отменён) или ожидание (wait) по часам автомата
+	type step struct {
+		wait    time.Duration
+		allowed bool
+		fail    bool
+		cancel  bool
+	}
+	tests := []struct {
+		name      string
+		threshold int
+		steps     []step
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+			steps: []step{
+				{allowed: true, fail: true},
+				{allowed: true, fail: true},
+				{allowed: true, fail: true},
+			},
+		},
+		{
+			name:      "opens after threshold failures in a row",
+			threshold: 2,
+			steps: []step{
+				{allowed: true, fail: true},
+				{allowed: true, fail: true},
+				{allowed: false},
+				{wait: 9 * time.Second, allowed: false},
+			},
+		},
+		{
+			name:      "success resets failures",
+			threshold: 2,
+			steps: []step{
+				{allowed: true, fail: true},
+				{allowed: true},
+				{allowed: true, fail: true},
+				{allowed: true},
+			},
+		},
+		{
+			name:      "successful probe closes",
+			threshold: 1,
+			steps: []step{
+				{allowed: true, fail: true},
+				{allowed: false},
+				{wait: 10 * time.Second, allowed: true},
+				{allowed: true},
+				{allowed: true},
+			},
+		},
+		{
+			name:      "failed probe opens again",
+			threshold: 1,
+			steps: []step{
+				{allowed: true, fail: true},
+				{wait: 10 * time.Second, allowed: true, fail: true},
+				{allowed: false},
+				{wait: 9 * time.Second, allowed: false},
+				{wait: time.Second, allowed: true},
+			},
+		},
+		{
+			name:      "cancelled requests are not counted",
+			threshold: 2,
+			steps: []step{
+				{allowed: true, fail: true},
+				{allowed: true, cancel: true},
+				{allowed: true, cancel: true},
+				{allowed: true, fail: true},
+				{allowed: false},
+			},
+		},
+		{
+			name:      "cancelled probe allows another probe",
+			threshold: 1,
+			steps: []step{
+				{allowed: true, fail: true},
+				{wait: 10 * time.Second, allowed: true, cancel: true},
+				{allowed: true, fail: true},
+				{allowed: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			b := newBreaker(tt.threshold, 10*time.Second)
+			b.now = func() time.Time { return now }
+			for i, s := range tt.steps {
+				now = now.Add(s.wait)
+				if got := b.allow(); got != s.allowed {
+					t.Fatalf("step %d: allow() = %v, want %v", i, got, s.allowed)
+				}
+				if !s.allowed {
+					continue
+				}
+				switch {
+				case s.cancel:
+					b.release()
+				case s.fail:
+					b.failure()
+				default:
+					b.success()
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerSingleProbe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBreaker(1, time.Second)
+	b.now = func() time.Time { return now }
+
+	b.allow()
+	b.failure()
+	now = now.Add(time.Second)
+	if !b.allow() {
+		t.Fatal("probe is not allowed after cooldown")
+	}
+	// Пока пробный запрос выполняется, остальные отклоняются
+	if b.allow() {
+		t.Error("second request is allowed while probing")
+	}
+}
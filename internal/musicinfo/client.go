@@ -0,0 +1,54 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound    = errors.New("song not found in music info API")
+	ErrUnavailable = errors.New("music info API is unavailable")
+)
+
+// SongInfo - сведения о песне из внешнего API.
+type SongInfo struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+	// Источник каждого заполненного поля (Field* -> имя источника).
+	// Заполняется MultiClient.
+	Sources map[string]string `json:"-" yaml:"-"`
+}
+
+// Поля сведений о песне в правилах объединения и в SongInfo.Sources.
+const (
+	FieldReleaseDate = "release_date"
+	FieldLyrics      = "lyrics"
+	FieldLink        = "link"
+)
+
+// fields возвращает указатели на поля сведений по их названиям.
+func (i *SongInfo) fields() map[string]*string {
+	return map[string]*string{
+		FieldReleaseDate: &i.ReleaseDate,
+		FieldLyrics:      &i.Text,
+		FieldLink:        &i.Link,
+	}
+}
+
+// Client получает сведения о песне из внешнего источника.
+type Client interface {
+	// GetInfo возвращает сведения о песне group - song.
+	// Если источник не знает песню, возвращает ErrNotFound.
+	GetInfo(ctx context.Context, group, song string) (*SongInfo, error)
+}
+
+// StatusError - неожиданный HTTP-статус ответа внешнего API.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("music info API returned status %d", e.StatusCode)
+}
@@ -0,0 +1,60 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileStore получает тексты песен из локального каталога вида
+// <dir>/<группа>/<название>.txt. Имена сравниваются без учёта регистра
+// и пробелов по краям. Остальные поля SongInfo не заполняются.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore создаёт новый FileStore для каталога dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// GetInfo возвращает текст песни group - song.
+// Если файла с текстом нет, возвращает ErrNotFound.
+func (s *FileStore) GetInfo(_ context.Context, group, song string) (*SongInfo, error) {
+	// Ищем файлы по списку каталога, а не по пути из имени песни,
+	// чтобы имя вида "../x" не выводило за пределы каталога
+	groupDir, err := findEntry(s.dir, group, true)
+	if err != nil {
+		return nil, err
+	}
+	file, err := findEntry(groupDir, song+".txt", false)
+	if err != nil {
+		return nil, err
+	}
+	text, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &SongInfo{Text: strings.TrimSpace(strings.ReplaceAll(string(text), "\r\n", "\n"))}, nil
+}
+
+// findEntry возвращает путь к элементу каталога dir с именем name.
+func findEntry(dir, name string, isDir bool) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimSpace(name)
+	for _, entry := range entries {
+		if entry.IsDir() == isDir && strings.EqualFold(strings.TrimSpace(entry.Name()), name) {
+			return filepath.Join(dir, entry.Name()), nil
+		}
+	}
+	return "", ErrNotFound
+}
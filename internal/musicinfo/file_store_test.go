@@ -0,0 +1,65 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "Muse"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Muse", "Uprising.txt"), []byte("\r\nParanoia is in bloom\r\n\r\nThey will not force us\r\n  "), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Каталог с именем файла не считается текстом песни
+	if err := os.MkdirAll(filepath.Join(dir, "Muse", "Starlight.txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		dir         string
+		group, song string
+		want        string
+		wantErr     error
+	}{
+		{name: "song", group: "Muse", song: "Uprising", want: "Paranoia is in bloom\n\nThey will not force us"},
+		{name: "case and spaces are ignored", group: " muse ", song: "UPRISING", want: "Paranoia is in bloom\n\nThey will not force us"},
+		{name: "unknown song", group: "Muse", song: "Hysteria", wantErr: ErrNotFound},
+		{name: "unknown group", group: "Queen", song: "Uprising", wantErr: ErrNotFound},
+		{name: "directory instead of file", group: "Muse", song: "Starlight", wantErr: ErrNotFound},
+		{name: "path outside the group", group: "Muse", song: "../secret", wantErr: ErrNotFound},
+		{name: "path outside the store", group: "..", song: "secret", wantErr: ErrNotFound},
+		{name: "missing store", dir: filepath.Join(dir, "missing"), group: "Muse", song: "Uprising", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storeDir := tt.dir
+			if storeDir == "" {
+				storeDir = dir
+			}
+			info, err := NewFileStore(storeDir).GetInfo(context.Background(), tt.group, tt.song)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetInfo(%q, %q) error = %v, want %v", tt.group, tt.song, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetInfo(%q, %q): %v", tt.group, tt.song, err)
+			}
+			if want := (SongInfo{Text: tt.want}); !reflect.DeepEqual(*info, want) {
+				t.Errorf("GetInfo(%q, %q) = %+v, want %+v", tt.group, tt.song, *info, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package musicinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Config - настройки HTTP-клиента внешнего API.
+type Config struct {
+	// Адрес метода получения сведений о песне, например http://host/info
+	URL string
+	// Таймаут одной попытки запроса
+	Timeout time.Duration
+	// Количество повторов после первой неудачной попытки
+	MaxRetries int
+	// Начальная и максимальная задержка между повторами. Если Retry-After
+	// ответа больше максимальной задержки, запрос не повторяется.
+	RetryBackoff    time.Duration
+	RetryBackoffMax time.Duration
+	// Число ошибок подряд, после которого запросы временно не выполняются (0 - без ограничения)
+	BreakerThreshold int
+	// Время, в течение которого запросы не выполняются после размыкания
+	BreakerCooldown time.Duration
+}
+
+// HTTPClient получает сведения о песне по HTTP: GET URL?group=...&song=...
+// Ошибки сети, ответы 5xx и 429 повторяются с экспоненциальной задержкой.
+type HTTPClient struct {
+	cfg     Config
+	http    *http.Client
+	breaker *breaker
+	log     *slog.Logger
+}
+
+// NewHTTPClient создаёт новый HTTPClient.
+func NewHTTPClient(cfg Config, log *slog.Logger) *HTTPClient {
+	return &HTTPClient{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		log:     log,
+	}
+}
+
+// GetInfo возвращает сведения о песне group - song.
+// Если автомат защиты разомкнут, сразу возвращает ErrUnavailable.
+func (c *HTTPClient) GetInfo(ctx context.Context, group, song string) (*SongInfo, error) {
+	if !c.breaker.allow() {
+		return nil, ErrUnavailable
+	}
+
+	u, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid music info API url: %w", err)
+	}
+	query := u.Query()
+	query.Set("group", group)
+	query.Set("song", song)
+	u.RawQuery = query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		info, retryAfter, err := c.do(ctx, u.String())
+		switch {
+		case err == nil || errors.Is(err, ErrNotFound):
+			c.breaker.success()
+			return info, err
+		case !retryable(err):
+			c.failure(ctx)
+			return nil, err
+		}
+		if attempt >= c.cfg.MaxRetries || ctx.Err() != nil {
+			c.failure(ctx)
+			return nil, err
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > delay {
+			// Не ждём дольше RetryBackoffMax и дольше, чем осталось до дедлайна ctx:
+			// такой повтор всё равно не успеет выполниться
+			if !c.canWait(ctx, retryAfter) {
+				c.failure(ctx)
+				return nil, err
+			}
+			delay = retryAfter
+		}
+		c.log.Warn("music info request failed, retrying",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+		select {
+		case <-ctx.Done():
+			c.failure(ctx)
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// do выполняет одну попытку запроса. retryAfter - задержка из заголовка
+// Retry-After ответа 429/503, если он задан в секундах.
+func (c *HTTPClient) do(ctx context.Context, rawURL string) (info *SongInfo, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, 0, ErrNotFound
+	default:
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, retryAfter, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	info = &SongInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode music info API response: %w", err)
+	}
+	return info, 0, nil
+}
+
+// backoff возвращает задержку перед повтором номер attempt+1:
+// RetryBackoff * 2^attempt со случайным разбросом, не больше RetryBackoffMax.
+func (c *HTTPClient) backoff(attempt int) time.Duration {
+	delay := c.cfg.RetryBackoff << attempt
+	if c.cfg.RetryBackoffMax > 0 && (delay > c.cfg.RetryBackoffMax || delay <= 0) {
+		delay = c.cfg.RetryBackoffMax
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Разброс ±25%, чтобы повторы разных запросов не совпадали по времени
+	jitter := time.Duration(rand.Int64N(int64(delay)/2+1)) - delay/4
+	return delay + jitter
+}
+
+// failure учитывает неудачный запрос в автомате защиты. Запрос, прерванный
+// отменой или дедлайном ctx, не говорит о состоянии API и не учитывается.
+func (c *HTTPClient) failure(ctx context.Context) {
+	if ctx.Err() != nil {
+		c.breaker.release()
+		return
+	}
+	c.breaker.failure()
+}
+
+// canWait сообщает, можно ли отложить повтор на delay из заголовка Retry-After.
+func (c *HTTPClient) canWait(ctx context.Context, delay time.Duration) bool {
+	if c.cfg.RetryBackoffMax > 0 && delay > c.cfg.RetryBackoffMax {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && delay > time.Until(deadline) {
+		return false
+	}
+	return true
+}
+
+// retryable сообщает, имеет ли смысл повторить запрос после ошибки.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	// Ошибки сети и таймауты повторяем, ошибки разбора ответа - нет
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) && !errors.Is(err, context.Canceled)
+}
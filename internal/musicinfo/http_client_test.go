@@ -0,0 +1,259 @@
+package musicinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(url string, cfg Config) *HTTPClient {
+	cfg.URL = url
+	return NewHTTPClient(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestHTTPClientRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"seconds on 429", http.StatusTooManyRequests, "3", 3 * time.Second},
+		{"seconds on 503", http.StatusServiceUnavailable, "120", 2 * time.Minute},
+		{"no header", http.StatusServiceUnavailable, "", 0},
+		{"zero", http.StatusTooManyRequests, "0", 0},
+		{"negative", http.StatusTooManyRequests, "-5", 0},
+		{"http date is ignored", http.StatusServiceUnavailable, "Wed, 21 Oct 2015 07:28:00 GMT", 0},
+		{"garbage", http.StatusTooManyRequests, "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			_, retryAfter, err := testClient(srv.URL, Config{}).do(context.Background(), srv.URL)
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Fatalf("do() error = %v, want status %d", err, tt.status)
+			}
+			if retryAfter != tt.want {
+				t.Errorf("retryAfter = %v, want %v", retryAfter, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClientGetInfo(t *testing.T) {
+	info := SongInfo{ReleaseDate: "16.07.2006", Text: "Ooh baby", Link: "https://example.com"}
+	body, _ := json.Marshal(info)
+	tests := []struct {
+		name string
+		// Ответы сервера на попытки по порядку; последний повторяется
+		responses    []int
+		maxRetries   int
+		wantErr      error
+		wantStatus   int
+		wantAttempts int32
+	}{
+		{"ok", []int{http.StatusOK}, 2, nil, 0, 1},
+		{"not found is not retried", []int{http.StatusNotFound}, 2, ErrNotFound, 0, 1},
+		{"retry after 5xx", []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}, 2, nil, 0, 3},
+		{"retry after 429", []int{http.StatusTooManyRequests, http.StatusOK}, 2, nil, 0, 2},
+		{"retries exhausted", []int{http.StatusServiceUnavailable}, 2, nil, http.StatusServiceUnavailable, 3},
+		{"4xx is not retried", []int{http.StatusBadRequest}, 2, nil, http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				status := tt.responses[min(n, len(tt.responses))-1]
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					w.Write(body)
+				}
+			}))
+			defer srv.Close()
+
+			client := testClient(srv.URL, Config{MaxRetries: tt.maxRetries, RetryBackoff: time.Millisecond})
+			got, err := client.GetInfo(context.Background(), "Muse", "Supermassive Black Hole")
+			switch {
+			case tt.wantStatus != 0:
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus {
+					t.Fatalf("GetInfo() error = %v, want status %d", err, tt.wantStatus)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetInfo() error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("GetInfo(): %v", err)
+			case !reflect.DeepEqual(*got, info):
+				t.Errorf("GetInfo() = %+v, want %+v", *got, info)
+			}
+			if n := attempts.Load(); n != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", n, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestHTTPClientWaitsRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"releaseDate":"16.07.2006"}`)
+	}))
+	defer srv.Close()
+
+	client := testClient(srv.URL, Config{MaxRetries: 1, RetryBackoff: time.Millisecond, RetryBackoffMax: 2 * time.Second})
+	start := time.Now()
+	if _, err := client.GetInfo(context.Background(), "Muse", "Uprising"); err != nil {
+		t.Fatalf("GetInfo(): %v", err)
+	}
+	// Задержка из Retry-After больше RetryBackoff и заменяет её
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least Retry-After 1s", elapsed)
+	}
+}
+
+func TestHTTPClientDoesNotWaitLongRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		maxBackoff time.Duration
+		timeout    time.Duration
+	}{
+		{"beyond RetryBackoffMax", "120", time.Second, 0},
+		{"beyond context deadline", "5", 0, time.Second},
+		{"beyond both", "3600", 10 * time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.Header().Set("Retry-After", tt.retryAfter)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer srv.Close()
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			client := testClient(srv.URL, Config{MaxRetries: 3, RetryBackoff: time.Millisecond, RetryBackoffMax: tt.maxBackoff})
+			start := time.Now()
+			_, err := client.GetInfo(ctx, "Muse", "Uprising")
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("GetInfo() returned after %v, want no wait for Retry-After %ss", elapsed, tt.retryAfter)
+			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("GetInfo() error = %v, want status %d", err, http.StatusServiceUnavailable)
+			}
+			if n := attempts.Load(); n != 1 {
+				t.Errorf("attempts = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestHTTPClientBreaker(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := testClient(srv.URL, Config{BreakerThreshold: 2, BreakerCooldown: time.Hour})
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetInfo(context.Background(), "Muse", "Uprising"); errors.Is(err, ErrUnavailable) {
+			t.Fatalf("request %d: breaker opened too early", i+1)
+		}
+	}
+	if _, err := client.GetInfo(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("GetInfo() error = %v, want ErrUnavailable", err)
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("attempts = %d, want 2: open breaker must not send requests", n)
+	}
+}
+
+func TestHTTPClientBreakerIgnoresCancelled(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := testClient(srv.URL, Config{MaxRetries: 1, RetryBackoff: time.Minute, BreakerThreshold: 1, BreakerCooldown: time.Hour})
+	// Запрос отменяется, пока клиент ждёт повтора
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetInfo(ctx, "Muse", "Uprising"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetInfo() error = %v, want context.DeadlineExceeded", err)
+	}
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := client.GetInfo(cancelled, "Muse", "Uprising"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetInfo() error = %v, want context.Canceled", err)
+	}
+
+	// Отменённые запросы не размыкают автомат
+	client.cfg.MaxRetries = 0
+	if _, err := client.GetInfo(context.Background(), "Muse", "Uprising"); errors.Is(err, ErrUnavailable) {
+		t.Fatal("breaker opened after cancelled requests")
+	}
+	if _, err := client.GetInfo(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("GetInfo() error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"500", &StatusError{StatusCode: 500}, true},
+		{"503", &StatusError{StatusCode: 503}, true},
+		{"429", &StatusError{StatusCode: 429}, true},
+		{"400", &StatusError{StatusCode: 400}, false},
+		{"malformed json", fmt.Errorf("decode: %w", &json.SyntaxError{}), false},
+		{"wrong json type", fmt.Errorf("decode: %w", &json.UnmarshalTypeError{}), false},
+		{"canceled", fmt.Errorf("request: %w", context.Canceled), false},
+		{"timeout", fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"network", errors.New("connection refused"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidMergeRule = errors.New("invalid merge rule")
+
+// Provider - именованный источник сведений о песнях.
+type Provider struct {
+	Name   string
+	Client Client
+}
+
+// MultiClient получает сведения о песне из нескольких источников и объединяет их.
+// По умолчанию каждое поле берётся из первого по приоритету источника,
+// в котором оно не пустое; правило объединения может задать для поля
+// предпочтительный источник, который проверяется первым.
+type MultiClient struct {
+	providers []Provider
+	// Порядок проверки источников для каждого поля
+	order map[string][]int
+	log   *slog.Logger
+}
+
+// ParseProvider создаёт источник по описанию вида "имя=адрес".
+// Адрес http(s)://... задаёт API (HTTPClient с настройками cfg и адресом
+// вместо cfg.URL), адрес file://каталог - каталог с текстами песен (FileStore).
+func ParseProvider(spec string, cfg Config, log *slog.Logger) (Provider, error) {
+	name, target, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	name, target = strings.TrimSpace(name), strings.TrimSpace(target)
+	if !ok || name == "" || target == "" {
+		return Provider{}, fmt.Errorf("invalid music info provider %q, expected name=url", spec)
+	}
+	switch {
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		cfg.URL = target
+		return Provider{Name: name, Client: NewHTTPClient(cfg, log.With(slog.String("provider", name)))}, nil
+	case strings.HasPrefix(target, "file://"):
+		return Provider{Name: name, Client: NewFileStore(strings.TrimPrefix(target, "file://"))}, nil
+	default:
+		return Provider{}, fmt.Errorf("unsupported music info provider url %q", target)
+	}
+}
+
+// NewMultiClient создаёт MultiClient для источников в порядке приоритета.
+// prefer задаёт предпочтительный источник для полей (Field* -> имя источника).
+func NewMultiClient(providers []Provider, prefer map[string]string, log *slog.Logger) (*MultiClient, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("no music info providers")
+	}
+	index := make(map[string]int, len(providers))
+	for i, p := range providers {
+		if _, ok := index[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate music info provider %q", p.Name)
+		}
+		index[p.Name] = i
+	}
+
+	m := &MultiClient{providers: providers, order: map[string][]int{}, log: log}
+	for field := range (&SongInfo{}).fields() {
+		var order []int
+		if name, ok := prefer[field]; ok {
+			preferred, ok := index[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown provider %q for field %s", ErrInvalidMergeRule, name, field)
+			}
+			order = append(order, preferred)
+		}
+		for i := range providers {
+			if len(order) == 0 || order[0] != i {
+				order = append(order, i)
+			}
+		}
+		m.order[field] = order
+	}
+	for field := range prefer {
+		if _, ok := m.order[field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidMergeRule, field)
+		}
+	}
+	return m, nil
+}
+
+// ParseMergeRules разбирает правила объединения вида "lyrics=files".
+// Каждое правило задаёт предпочтительный источник для поля.
+func ParseMergeRules(rules []string) (map[string]string, error) {
+	prefer := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		field, provider, ok := strings.Cut(strings.TrimSpace(rule), "=")
+		field, provider = strings.TrimSpace(field), strings.TrimSpace(provider)
+		if !ok || field == "" || provider == "" {
+			return nil, fmt.Errorf("%w: %q, expected field=provider", ErrInvalidMergeRule, rule)
+		}
+		prefer[field] = provider
+	}
+	return prefer, nil
+}
+
+// GetInfo запрашивает сведения о песне во всех источниках одновременно
+// и объединяет ответы. Источники, не знающие песню или ответившие ошибкой,
+// пропускаются: ошибки записываются в журнал, а сведения объединяются
+// из остальных источников. Если не ответил ни один источник, возвращается
+// ошибка первого из них, а если песню не знает ни один - ErrNotFound.
+func (m *MultiClient) GetInfo(ctx context.Context, group, song string) (*SongInfo, error) {
+	results := make([]*SongInfo, len(m.providers))
+	errs := make([]error, len(m.providers))
+	var wg sync.WaitGroup
+	for i, p := range m.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.Client.GetInfo(ctx, group, song)
+		}()
+	}
+	wg.Wait()
+
+	found := false
+	var failure error
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			found = true
+		case !errors.Is(err, ErrNotFound):
+			if ctx.Err() == nil {
+				m.log.Warn("music info provider failed, skipped",
+					slog.String("provider", m.providers[i].Name), slog.Any("error", err))
+			}
+			if failure == nil {
+				failure = fmt.Errorf("provider %s: %w", m.providers[i].Name, err)
+			}
+		}
+	}
+	if !found {
+		// Песня может быть в источнике, который не ответил
+		if failure != nil {
+			return nil, failure
+		}
+		return nil, ErrNotFound
+	}
+
+	merged := &SongInfo{Sources: map[string]string{}}
+	for field, dst := range merged.fields() {
+		for _, i := range m.order[field] {
+			if results[i] == nil {
+				continue
+			}
+			if value := *results[i].fields()[field]; value != "" {
+				*dst = value
+				merged.Sources[field] = m.providers[i].Name
+				break
+			}
+		}
+	}
+	return merged, nil
+}
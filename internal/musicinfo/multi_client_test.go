@@ -0,0 +1,257 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// fakeClient возвращает заданные сведения или ошибку.
+type fakeClient struct {
+	info *SongInfo
+	err  error
+}
+
+func (c fakeClient) GetInfo(context.Context, string, string) (*SongInfo, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	info := *c.info
+	return &info, nil
+}
+
+func found(releaseDate, text, link string) fakeClient {
+	return fakeClient{info: &SongInfo{ReleaseDate: releaseDate, Text: text, Link: link}}
+}
+
+func TestMultiClientMerge(t *testing.T) {
+	notFound := fakeClient{err: ErrNotFound}
+	tests := []struct {
+		name    string
+		clients []fakeClient
+		prefer  map[string]string
+		want    *SongInfo
+	}{
+		{
+			name:    "fields from the first provider",
+			clients: []fakeClient{found("16.07.2006", "api text", "api link"), found("", "file text", "")},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "api text", Link: "api link",
+				Sources: map[string]string{FieldReleaseDate: "p0", FieldLyrics: "p0", FieldLink: "p0"},
+			},
+		},
+		{
+			name:    "empty fields from the next provider",
+			clients: []fakeClient{found("16.07.2006", "", ""), found("", "file text", ""), found("01.01.2000", "", "link")},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "file text", Link: "link",
+				Sources: map[string]string{FieldReleaseDate: "p0", FieldLyrics: "p1", FieldLink: "p2"},
+			},
+		},
+		{
+			name:    "preferred provider first",
+			clients: []fakeClient{found("16.07.2006", "api text", "api link"), found("", "file text", "")},
+			prefer:  map[string]string{FieldLyrics: "p1"},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "file text", Link: "api link",
+				Sources: map[string]string{FieldReleaseDate: "p0", FieldLyrics: "p1", FieldLink: "p0"},
+			},
+		},
+		{
+			name:    "empty preferred field falls back to priority order",
+			clients: []fakeClient{found("16.07.2006", "api text", "api link"), found("", "file text", "")},
+			prefer:  map[string]string{FieldLink: "p1"},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "api text", Link: "api link",
+				Sources: map[string]string{FieldReleaseDate: "p0", FieldLyrics: "p0", FieldLink: "p0"},
+			},
+		},
+		{
+			name:    "failed provider is skipped",
+			clients: []fakeClient{{err: ErrUnavailable}, found("", "file text", ""), found("16.07.2006", "", "")},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "file text",
+				Sources: map[string]string{FieldReleaseDate: "p2", FieldLyrics: "p1"},
+			},
+		},
+		{
+			name:    "failed preferred provider is skipped",
+			clients: []fakeClient{found("16.07.2006", "api text", ""), {err: errors.New("connection refused")}},
+			prefer:  map[string]string{FieldLyrics: "p1"},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "api text",
+				Sources: map[string]string{FieldReleaseDate: "p0", FieldLyrics: "p0"},
+			},
+		},
+		{
+			name:    "provider without the song is skipped",
+			clients: []fakeClient{notFound, found("16.07.2006", "file text", "")},
+			want: &SongInfo{
+				ReleaseDate: "16.07.2006", Text: "file text",
+				Sources: map[string]string{FieldReleaseDate: "p1", FieldLyrics: "p1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMultiClient(testProviders(tt.clients...), tt.prefer, testLogger())
+			if err != nil {
+				t.Fatalf("NewMultiClient: %v", err)
+			}
+			got, err := m.GetInfo(context.Background(), "Muse", "Uprising")
+			if err != nil {
+				t.Fatalf("GetInfo: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiClientErrors(t *testing.T) {
+	failure := errors.New("connection refused")
+	tests := []struct {
+		name    string
+		clients []fakeClient
+		wantErr error
+	}{
+		{"not found everywhere", []fakeClient{{err: ErrNotFound}, {err: ErrNotFound}}, ErrNotFound},
+		{"all providers failed", []fakeClient{{err: failure}, {err: ErrUnavailable}}, failure},
+		{"failure and not found", []fakeClient{{err: ErrNotFound}, {err: ErrUnavailable}}, ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMultiClient(testProviders(tt.clients...), nil, testLogger())
+			if err != nil {
+				t.Fatalf("NewMultiClient: %v", err)
+			}
+			if _, err := m.GetInfo(context.Background(), "Muse", "Uprising"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetInfo() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMultiClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []Provider
+		prefer    map[string]string
+		wantRule  bool
+	}{
+		{name: "no providers", providers: nil},
+		{name: "duplicate provider", providers: []Provider{{Name: "api", Client: fakeClient{}}, {Name: "api", Client: fakeClient{}}}},
+		{name: "unknown provider in rule", providers: testProviders(fakeClient{}), prefer: map[string]string{FieldLyrics: "files"}, wantRule: true},
+		{name: "unknown field in rule", providers: testProviders(fakeClient{}), prefer: map[string]string{"title": "p0"}, wantRule: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMultiClient(tt.providers, tt.prefer, testLogger())
+			if err == nil {
+				t.Fatal("NewMultiClient() error = nil, want error")
+			}
+			if got := errors.Is(err, ErrInvalidMergeRule); got != tt.wantRule {
+				t.Errorf("NewMultiClient() error = %v, ErrInvalidMergeRule = %v, want %v", err, got, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestParseMergeRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "no rules", rules: nil, want: map[string]string{}},
+		{name: "rules", rules: []string{"lyrics=files", " link = api "}, want: map[string]string{FieldLyrics: "files", FieldLink: "api"}},
+		{name: "last rule wins", rules: []string{"lyrics=files", "lyrics=api"}, want: map[string]string{FieldLyrics: "api"}},
+		{name: "no separator", rules: []string{"lyrics"}, wantErr: true},
+		{name: "empty field", rules: []string{"=files"}, wantErr: true},
+		{name: "empty provider", rules: []string{"lyrics= "}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMergeRules(tt.rules)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMergeRule) {
+					t.Errorf("ParseMergeRules(%q) error = %v, want ErrInvalidMergeRule", tt.rules, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMergeRules(%q): %v", tt.rules, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMergeRules(%q) = %v, want %v", tt.rules, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantName string
+		wantURL  string
+		wantDir  string
+		wantErr  bool
+	}{
+		{name: "http", spec: "api=http://localhost:8081/info", wantName: "api", wantURL: "http://localhost:8081/info"},
+		{name: "https with spaces", spec: " api = https://example.com/info ", wantName: "api", wantURL: "https://example.com/info"},
+		{name: "file", spec: "files=file:///srv/lyrics", wantName: "files", wantDir: "/srv/lyrics"},
+		{name: "no separator", spec: "http://localhost:8081/info", wantErr: true},
+		{name: "empty name", spec: "=http://localhost:8081/info", wantErr: true},
+		{name: "empty url", spec: "api=", wantErr: true},
+		{name: "unsupported scheme", spec: "api=ftp://example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseProvider(tt.spec, Config{}, testLogger())
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseProvider(%q) error = nil, want error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProvider(%q): %v", tt.spec, err)
+			}
+			if p.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", p.Name, tt.wantName)
+			}
+			switch c := p.Client.(type) {
+			case *HTTPClient:
+				if tt.wantURL == "" || c.cfg.URL != tt.wantURL {
+					t.Errorf("HTTPClient url = %q, want %q", c.cfg.URL, tt.wantURL)
+				}
+			case *FileStore:
+				if tt.wantDir == "" || c.dir != tt.wantDir {
+					t.Errorf("FileStore dir = %q, want %q", c.dir, tt.wantDir)
+				}
+			default:
+				t.Errorf("client = %T, want *HTTPClient or *FileStore", p.Client)
+			}
+		})
+	}
+}
+
+// testProviders возвращает источники p0, p1, ... в порядке приоритета.
+func testProviders(clients ...fakeClient) []Provider {
+	providers := make([]Provider, len(clients))
+	for i, c := range clients {
+		providers[i] = Provider{Name: "p" + strconv.Itoa(i), Client: c}
+	}
+	return providers
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"music-test-lib/internal/domain"
+)
+
+var (
+	ErrAlbumNotFound     = errors.New("album not found")
+	ErrTrackSongNotFound = errors.New("album track song not found")
+	ErrDuplicateTrack    = errors.New("duplicate album track")
+)
+
+// AlbumInput - данные альбома при добавлении и изменении.
+// Пустые ReleaseDate и CoverURL означают, что значение не задано.
+type AlbumInput struct {
+	Title       string
+	ArtistID    string
+	ReleaseDate string
+	CoverURL    string
+}
+
+// AlbumFilter описывает условия отбора альбомов.
+// Пустые поля не участвуют в фильтрации.
+type AlbumFilter struct {
+	// Часть названия альбома
+	Title    string
+	ArtistID string
+}
+
+// AlbumRepository хранит альбомы и их треки.
+type AlbumRepository struct {
+	db *sqlx.DB
+}
+
+// NewAlbumRepository создает новый AlbumRepository.
+func NewAlbumRepository(db *sqlx.DB) *AlbumRepository {
+	return &AlbumRepository{db: db}
+}
+
+// albumColumns - столбцы альбома в порядке, ожидаемом scanAlbum.
+const albumColumns = "al.id, al.title, al.artist_id, ar.name, " +
+	"COALESCE(to_char(al.release_date, 'YYYY-MM-DD'), ''), COALESCE(al.cover_url, ''), " +
+	"(SELECT count(*) FROM album_tracks t WHERE t.album_id = al.id)"
+
+// albumsFrom - источник строк для albumColumns.
+const albumsFrom = " FROM albums al JOIN artists ar ON ar.id = al.artist_id"
+
+func scanAlbum(row rowScanner) (domain.Album, error) {
+	var album domain.Album
+	err := row.Scan(&album.ID, &album.Title, &album.ArtistID, &album.Artist,
+		&album.ReleaseDate, &album.CoverURL, &album.TrackCount)
+	return album, err
+}
+
+// GetAlbums возвращает страницу альбомов, удовлетворяющих фильтру, в порядке
+// дат релиза (альбомы без даты - в конце), и общее количество таких альбомов.
+func (r *AlbumRepository) GetAlbums(filter AlbumFilter, page Pagination) ([]domain.Album, int, error) {
+	var b queryBuilder
+	b.match("al.title", StringFilter{Value: filter.Title, Mode: MatchContains})
+	if filter.ArtistID != "" {
+		b.where("al.artist_id = " + b.arg(filter.ArtistID))
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT count(*) FROM albums al"+b.whereClause(), b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + albumColumns + albumsFrom + b.whereClause() +
+		" ORDER BY al.release_date NULLS LAST, lower(al.title), al.id" +
+		" LIMIT " + b.arg(page.Limit) + " OFFSET " + b.arg(page.Offset)
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	albums := []domain.Album{}
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, total, rows.Err()
+}
+
+// GetAlbumByID возвращает альбом по его ID вместе с треками
+// в порядке номеров дисков и треков.
+func (r *AlbumRepository) GetAlbumByID(id string) (*domain.Album, error) {
+	album, err := scanAlbum(r.db.QueryRow("SELECT "+albumColumns+albumsFrom+" WHERE al.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrAlbumNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		"SELECT t.disc_number, t.track_number, s.id, s.group_name, s.song_name "+
+			"FROM album_tracks t JOIN songs s ON s.id = t.song_id "+
+			"WHERE t.album_id = $1 ORDER BY t.disc_number, t.track_number", id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	album.Tracks = []domain.AlbumTrack{}
+	for rows.Next() {
+		var track domain.AlbumTrack
+		if err := rows.Scan(&track.DiscNumber, &track.TrackNumber, &track.SongID, &track.Group, &track.Title); err != nil {
+			return nil, err
+		}
+		album.Tracks = append(album.Tracks, track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// AddAlbum добавляет альбом без треков. Если исполнителя нет,
+// возвращает ErrArtistNotFound.
+func (r *AlbumRepository) AddAlbum(in AlbumInput) (*domain.Album, error) {
+	var id string
+	err := r.db.QueryRow(
+		"INSERT INTO albums (title, artist_id, release_date, cover_url) "+
+			"VALUES ($1, $2, NULLIF($3, '')::date, NULLIF($4, '')) RETURNING id",
+		in.Title, in.ArtistID, in.ReleaseDate, in.CoverURL,
+	).Scan(&id)
+	if err != nil {
+		return nil, artistReferenceError(err)
+	}
+	return r.GetAlbumByID(id)
+}
+
+// UpdateAlbum заменяет данные альбома; треки не изменяются.
+// Если альбома нет, возвращает ErrAlbumNotFound, если исполнителя - ErrArtistNotFound.
+func (r *AlbumRepository) UpdateAlbum(id string, in AlbumInput) (*domain.Album, error) {
+	res, err := r.db.Exec(
+		"UPDATE albums SET title = $2, artist_id = $3, release_date = NULLIF($4, '')::date, "+
+			"cover_url = NULLIF($5, '') WHERE id = $1",
+		id, in.Title, in.ArtistID, in.ReleaseDate, in.CoverURL,
+	)
+	if err != nil {
+		return nil, artistReferenceError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrAlbumNotFound
+	}
+	return r.GetAlbumByID(id)
+}
+
+// SetAlbumTracks заменяет треки альбома. Если какой-то песни нет,
+// возвращает ErrTrackSongNotFound и оставляет треки без изменений.
+func (r *AlbumRepository) SetAlbumTracks(id string, tracks []domain.AlbumTrack) (*domain.Album, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Блокируем альбом, чтобы одновременные замены треков выполнялись по очереди
+	var locked string
+	err = tx.QueryRow("SELECT id FROM albums WHERE id = $1 FOR UPDATE", id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, ErrAlbumNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM album_tracks WHERE album_id = $1", id); err != nil {
+		return nil, err
+	}
+	songIDs := make([]string, len(tracks))
+	discs := make([]int64, len(tracks))
+	numbers := make([]int64, len(tracks))
+	for i, t := range tracks {
+		songIDs[i], discs[i], numbers[i] = t.SongID, int64(t.DiscNumber), int64(t.TrackNumber)
+	}
+	_, err = tx.Exec(
+		"INSERT INTO album_tracks (album_id, song_id, disc_number, track_number) "+
+			"SELECT $1::int, * FROM unnest($2::int[], $3::int[], $4::int[])",
+		id, pq.Array(songIDs), pq.Array(discs), pq.Array(numbers),
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case foreignKeyViolation:
+			return nil, ErrTrackSongNotFound
+		case uniqueViolation:
+			return nil, ErrDuplicateTrack
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetAlbumByID(id)
+}
+
+// DeleteAlbum удаляет альбом вместе с его треками; песни остаются в библиотеке.
+func (r *AlbumRepository) DeleteAlbum(id string) error {
+	res, err := r.db.Exec("DELETE FROM albums WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAlbumNotFound
+	}
+	return nil
+}
+
+// artistReferenceError преобразует нарушение внешнего ключа на исполнителя
+// в ErrArtistNotFound.
+func artistReferenceError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return ErrArtistNotFound
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"music-test-lib/internal/domain"
+)
+
+var (
+	ErrArtistNotFound  = errors.New("artist not found")
+	ErrArtistExists    = errors.New("artist already exists")
+	ErrArtistHasSongs  = errors.New("artist has songs")
+	ErrArtistHasAlbums = errors.New("artist has albums")
+)
+
+// foreignKeyViolation - код ошибки PostgreSQL при нарушении внешнего ключа.
+const foreignKeyViolation = "23503"
+
+// ArtistExistsError сообщает, что исполнитель с таким же названием
+// (без учёта регистра и лишних пробелов) уже есть в библиотеке.
+type ArtistExistsError struct {
+	ExistingID string
+}
+
+func (e *ArtistExistsError) Error() string {
+	return fmt.Sprintf("%s: id %s", ErrArtistExists, e.ExistingID)
+}
+
+func (e *ArtistExistsError) Is(target error) bool {
+	return target == ErrArtistExists
+}
+
+// ArtistRepository хранит исполнителей.
+type ArtistRepository struct {
+	db *sqlx.DB
+}
+
+// NewArtistRepository создает новый ArtistRepository.
+func NewArtistRepository(db *sqlx.DB) *ArtistRepository {
+	return &ArtistRepository{db: db}
+}
+
+// artistColumns - столбцы исполнителя в порядке, ожидаемом scanArtist.
+const artistColumns = "a.id, a.name, (SELECT count(*) FROM songs s WHERE s.artist_id = a.id)"
+
+func scanArtist(row rowScanner) (domain.Artist, error) {
+	var artist domain.Artist
+	err := row.Scan(&artist.ID, &artist.Name, &artist.SongCount)
+	return artist, err
+}
+
+// GetArtists возвращает страницу исполнителей, название которых содержит name
+// (если он задан), в алфавитном порядке, и общее количество таких исполнителей.
+func (r *ArtistRepository) GetArtists(name string, page Pagination) ([]domain.Artist, int, error) {
+	var b queryBuilder
+	b.match("a.name", StringFilter{Value: name, Mode: MatchContains})
+
+	var total int
+	if err := r.db.QueryRow("SELECT count(*) FROM artists a"+b.whereClause(), b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + artistColumns + " FROM artists a" + b.whereClause() +
+		" ORDER BY lower(a.name), a.id LIMIT " + b.arg(page.Limit) + " OFFSET " + b.arg(page.Offset)
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	artists := []domain.Artist{}
+	for rows.Next() {
+		artist, err := scanArtist(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		artists = append(artists, artist)
+	}
+	return artists, total, rows.Err()
+}
+
+// GetArtistByID возвращает исполнителя по его ID.
+func (r *ArtistRepository) GetArtistByID(id string) (*domain.Artist, error) {
+	artist, err := scanArtist(r.db.QueryRow("SELECT "+artistColumns+" FROM artists a WHERE a.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrArtistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// AddArtist добавляет исполнителя. Если исполнитель с таким названием уже есть,
+// возвращает *ArtistExistsError.
+func (r *ArtistRepository) AddArtist(name string) (*domain.Artist, error) {
+	var artist domain.Artist
+	err := r.db.QueryRow("INSERT INTO artists (name) VALUES ($1) RETURNING id, name", name).
+		Scan(&artist.ID, &artist.Name)
+	if err != nil {
+		return nil, r.existsError(err, name, "")
+	}
+	return &artist, nil
+}
+
+// RenameArtist переименовывает исполнителя и обновляет название группы в его песнях
+// и версии песен, в участниках которых он указан.
+// Если исполнителя нет, возвращает ErrArtistNotFound, если название занято
+// другим исполнителем - *ArtistExistsError.
+func (r *ArtistRepository) RenameArtist(id, name string) (*domain.Artist, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE artists SET name = $2 WHERE id = $1", id, name)
+	if err != nil {
+		return nil, r.existsError(err, name, id)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrArtistNotFound
+	}
+	if _, err := tx.Exec("UPDATE songs SET group_name = $2 WHERE artist_id = $1 AND group_name <> $2", id, name); err != nil {
+		return nil, err
+	}
+	// Название исполнителя входит в участников песен, где он указан:
+	// их версии изменяются, даже если основной исполнитель другой
+	if err := touchLinkedSongs(tx, "SELECT song_id FROM song_credits WHERE artist_id = $1", id); err != nil {
+		return nil, err
+	}
+	artist, err := scanArtist(tx.QueryRow("SELECT "+artistColumns+" FROM artists a WHERE a.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// DeleteArtist удаляет исполнителя. Исполнителя с песнями или альбомами
+// удалить нельзя: возвращается ErrArtistHasSongs или ErrArtistHasAlbums.
+func (r *ArtistRepository) DeleteArtist(id string) error {
+	res, err := r.db.Exec("DELETE FROM artists WHERE id = $1", id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		if pqErr.Table == "albums" {
+			return ErrArtistHasAlbums
+		}
+		return ErrArtistHasSongs
+	}
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrArtistNotFound
+	}
+	return nil
+}
+
+// existsError преобразует нарушение уникальности названия исполнителя
+// в *ArtistExistsError с ID исполнителя, отличного от exceptID.
+func (r *ArtistRepository) existsError(err error, name, exceptID string) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return err
+	}
+	var existingID string
+	findErr := r.db.QueryRow(
+		"SELECT id FROM artists WHERE normalize_name(name) = normalize_name($1) AND id::text <> $2", name, exceptID,
+	).Scan(&existingID)
+	if findErr != nil {
+		// Исполнителя успели удалить или переименовать - сообщаем исходную ошибку
+		return err
+	}
+	return &ArtistExistsError{ExistingID: existingID}
+}
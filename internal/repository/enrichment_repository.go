@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"music-test-lib/internal/domain"
+	"time"
+)
+
+// EnrichmentJob - задание на получение данных о песне из внешнего API.
+// Attempts - номер текущей попытки, начиная с 1.
+type EnrichmentJob struct {
+	ID       int64
+	SongID   string
+	Group    string
+	Title    string
+	Attempts int
+}
+
+// EnrichmentRepository хранит задания на получение данных о песнях.
+type EnrichmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewEnrichmentRepository создает новый EnrichmentRepository.
+func NewEnrichmentRepository(db *sqlx.DB) *EnrichmentRepository {
+	return &EnrichmentRepository{db: db}
+}
+
+// Claim забирает одно готовое к выполнению задание. Задание откладывается
+// на время lease, чтобы его не взял другой обработчик; если обработчик
+// не завершит задание за это время, оно будет выполнено повторно.
+// Если готовых заданий нет, возвращает nil.
+func (r *EnrichmentRepository) Claim(lease time.Duration) (*EnrichmentJob, error) {
+	var job EnrichmentJob
+	err := r.db.QueryRow(`
+		WITH next AS (
+			SELECT id FROM enrichment_jobs
+			WHERE next_run_at <= now()
+			ORDER BY next_run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE enrichment_jobs j
+		SET attempts = j.attempts + 1, next_run_at = now() + $1 * interval '1 millisecond'
+		FROM next, songs s
+		WHERE j.id = next.id AND s.id = j.song_id
+		RETURNING j.id, j.song_id, s.group_name, s.song_name, j.attempts`,
+		lease.Milliseconds(),
+	).Scan(&job.ID, &job.SongID, &job.Group, &job.Title, &job.Attempts)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Complete сохраняет полученные данные о песне и удаляет задание.
+func (r *EnrichmentRepository) Complete(job *EnrichmentJob, song domain.SongWithoutID) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Если песню удалили, задание удалено вместе с ней
+	if _, err := saveExternalInfo(tx, job.SongID, song); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveExternalInfo сохраняет данные песни, полученные из внешнего API,
+// помечает их как полученные и удаляет задание на их получение, если оно есть.
+// Поле заменяется, только если новое значение не пустое, а сохранённое пустое
+// или тоже получено из внешнего источника: значения, заданные вручную, не затираются.
+func saveExternalInfo(tx *sqlx.Tx, id string, song domain.SongWithoutID) (domain.Song, error) {
+	saved, err := scanSong(tx.QueryRow(
+		"UPDATE songs SET "+
+			"release_date = CASE WHEN "+replaceReleaseDate+" THEN $2::date ELSE release_date END, "+
+			"lyrics = CASE WHEN "+replaceLyrics+" THEN $3 ELSE lyrics END, "+
+			"link = CASE WHEN "+replaceLink+" THEN $4 ELSE link END, "+
+			"sources = sources || jsonb_strip_nulls(jsonb_build_object("+
+			"'release_date', CASE WHEN "+replaceReleaseDate+" THEN $5::jsonb -> 'release_date' END, "+
+			"'lyrics', CASE WHEN "+replaceLyrics+" THEN $5::jsonb -> 'lyrics' END, "+
+			"'link', CASE WHEN "+replaceLink+" THEN $5::jsonb -> 'link' END)), "+
+			"stanzas = CASE WHEN "+replaceLyrics+" THEN $6::jsonb ELSE stanzas END, "+
+			"enrichment_status = $7, enrichment_error = NULL, refreshed_at = now() "+
+			"WHERE id = $1 RETURNING "+songColumns,
+		id, song.ReleaseDate, song.Lyrics, song.Link, sourcesJSON(song.Sources), stanzasJSON(song.Stanzas), domain.EnrichmentReady,
+	))
+	if err != nil {
+		return domain.Song{}, err
+	}
+	if _, err := tx.Exec("DELETE FROM enrichment_jobs WHERE song_id = $1", id); err != nil {
+		return domain.Song{}, err
+	}
+	return saved, nil
+}
+
+// Условия замены полей в saveExternalInfo (см. domain.ExternalValueApplies).
+// В UPDATE выражения видят значения строки до изменения.
+const (
+	replaceReleaseDate = "($2 <> '' AND (release_date IS NULL OR sources ? 'release_date'))"
+	replaceLyrics      = "($3 <> '' AND (lyrics = '' OR sources ? 'lyrics'))"
+	replaceLink        = "($4 <> '' AND (COALESCE(link, '') = '' OR sources ? 'link'))"
+)
+
+// Retry откладывает задание до nextRunAt и сохраняет ошибку в задании и песне.
+func (r *EnrichmentRepository) Retry(job *EnrichmentJob, jobErr error, nextRunAt time.Time) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE enrichment_jobs SET next_run_at = $2, last_error = $3 WHERE id = $1",
+		job.ID, nextRunAt, jobErr.Error())
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE songs SET enrichment_error = $2 WHERE id = $1", job.SongID, jobErr.Error()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Fail помечает песню как EnrichmentFailed с ошибкой jobErr и удаляет задание.
+func (r *EnrichmentRepository) Fail(job *EnrichmentJob, jobErr error) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE songs SET enrichment_status = $2, enrichment_error = $3 WHERE id = $1",
+		job.SongID, domain.EnrichmentFailed, jobErr.Error())
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM enrichment_jobs WHERE id = $1", job.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"music-test-lib/internal/domain"
+)
+
+var (
+	ErrGenreNotFound     = errors.New("genre not found")
+	ErrGenreExists       = errors.New("genre already exists")
+	ErrGenreHasSubgenres = errors.New("genre has subgenres")
+	ErrGenreCycle        = errors.New("genre cannot be its own ancestor")
+	ErrParentNotFound    = errors.New("parent genre not found")
+)
+
+// GenreRepository хранит жанры.
+type GenreRepository struct {
+	db *sqlx.DB
+}
+
+// NewGenreRepository создает новый GenreRepository.
+func NewGenreRepository(db *sqlx.DB) *GenreRepository {
+	return &GenreRepository{db: db}
+}
+
+// genreSongIDs - запрос ID песен жанра $1 и всех его поджанров.
+const genreSongIDs = "WITH RECURSIVE sub AS (" +
+	"SELECT id FROM genres WHERE id = $1 " +
+	"UNION SELECT g.id FROM genres g JOIN sub ON g.parent_id = sub.id" +
+	") SELECT song_id FROM song_genres WHERE genre_id IN (SELECT id FROM sub)"
+
+// genreColumns - столбцы жанра в порядке, ожидаемом scanGenre.
+const genreColumns = "g.id, g.name, COALESCE(g.parent_id::text, ''), " +
+	"(SELECT count(*) FROM song_genres sg WHERE sg.genre_id = g.id)"
+
+func scanGenre(row rowScanner) (domain.Genre, error) {
+	var genre domain.Genre
+	err := row.Scan(&genre.ID, &genre.Name, &genre.ParentID, &genre.SongCount)
+	return genre, err
+}
+
+// GetGenres возвращает все жанры в алфавитном порядке без построения дерева.
+func (r *GenreRepository) GetGenres() ([]domain.Genre, error) {
+	rows, err := r.db.Query("SELECT " + genreColumns + " FROM genres g ORDER BY lower(g.name), g.id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []domain.Genre{}
+	for rows.Next() {
+		genre, err := scanGenre(rows)
+		if err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, rows.Err()
+}
+
+// GetGenreByID возвращает жанр по его ID без поджанров.
+func (r *GenreRepository) GetGenreByID(id string) (*domain.Genre, error) {
+	genre, err := scanGenre(r.db.QueryRow("SELECT "+genreColumns+" FROM genres g WHERE g.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrGenreNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// AddGenre добавляет жанр. Пустой parentID означает жанр верхнего уровня.
+func (r *GenreRepository) AddGenre(name, parentID string) (*domain.Genre, error) {
+	var id string
+	err := r.db.QueryRow(
+		"INSERT INTO genres (name, parent_id) VALUES ($1, NULLIF($2, '')::int) RETURNING id", name, parentID,
+	).Scan(&id)
+	if err != nil {
+		return nil, genreError(err)
+	}
+	return r.GetGenreByID(id)
+}
+
+// UpdateGenre изменяет название и родительский жанр. Жанр нельзя сделать
+// поджанром самого себя или своего поджанра: возвращается ErrGenreCycle.
+func (r *GenreRepository) UpdateGenre(id, name, parentID string) (*domain.Genre, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Одновременные переносы жанров могли бы вместе образовать цикл,
+	// поэтому изменения дерева выполняются по очереди
+	if _, err := tx.Exec("LOCK TABLE genres IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
+	if parentID != "" {
+		var cycle bool
+		err := tx.QueryRow(
+			"WITH RECURSIVE sub AS ("+
+				"SELECT id FROM genres WHERE id = $1 "+
+				"UNION SELECT g.id FROM genres g JOIN sub ON g.parent_id = sub.id"+
+				") SELECT EXISTS (SELECT 1 FROM sub WHERE id = $2)",
+			id, parentID,
+		).Scan(&cycle)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, ErrGenreCycle
+		}
+	}
+
+	res, err := tx.Exec("UPDATE genres SET name = $2, parent_id = NULLIF($3, '')::int WHERE id = $1", id, name, parentID)
+	if err != nil {
+		return nil, genreError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrGenreNotFound
+	}
+	if err := touchLinkedSongs(tx, genreSongIDs, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetGenreByID(id)
+}
+
+// DeleteGenre удаляет жанр; песни теряют этот жанр. Жанр с поджанрами
+// удалить нельзя: возвращается ErrGenreHasSubgenres.
+func (r *GenreRepository) DeleteGenre(id string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Версии песен изменяются до удаления, пока связи с жанром ещё есть
+	if err := touchLinkedSongs(tx, genreSongIDs, id); err != nil {
+		return err
+	}
+	res, err := tx.Exec("DELETE FROM genres WHERE id = $1", id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return ErrGenreHasSubgenres
+	}
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrGenreNotFound
+	}
+	return tx.Commit()
+}
+
+// genreError преобразует ошибки ограничений таблицы жанров.
+func genreError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return ErrGenreExists
+		case foreignKeyViolation:
+			return ErrParentNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// IdempotencyKey - сохранённый ключ идемпотентности запроса на добавление песни.
+// SongID пуст, пока запрос с этим ключом ещё выполняется.
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	SongID      string
+	// Время, когда ключ был занят. Отличает текущее занятие ключа от
+	// предыдущего, признанного брошенным
+	ReservedAt time.Time
+}
+
+// IdempotencyRepository хранит ключи идемпотентности запросов.
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyRepository создает новый IdempotencyRepository.
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve занимает ключ для запроса с хешем requestHash.
+// Ключ, запрос с которым не завершился за reserveTimeout (например, сервис
+// остановился до вызова Complete), считается брошенным, а ключ старше ttl -
+// истёкшим: такие ключи занимаются заново. ttl = 0 - ключи не истекают.
+// Если ключ уже занят, возвращает сохранённую запись и ErrIdempotencyKeyExists.
+func (r *IdempotencyRepository) Reserve(key, requestHash string, reserveTimeout, ttl time.Duration) (*IdempotencyKey, error) {
+	reserved := IdempotencyKey{Key: key, RequestHash: requestHash}
+	err := r.db.QueryRow(`
+		INSERT INTO idempotency_keys (key, request_hash) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, song_id = NULL, created_at = now()
+		WHERE (idempotency_keys.song_id IS NULL AND idempotency_keys.created_at < now() - $3 * interval '1 millisecond')
+		   OR ($4::bigint > 0 AND idempotency_keys.created_at < now() - $4::bigint * interval '1 millisecond')
+		RETURNING created_at`,
+		key, requestHash, reserveTimeout.Milliseconds(), ttl.Milliseconds(),
+	).Scan(&reserved.ReservedAt)
+	if err == nil {
+		return &reserved, nil
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var existing IdempotencyKey
+	var songID sql.NullString
+	err = r.db.QueryRow(
+		"SELECT key, request_hash, song_id, created_at FROM idempotency_keys WHERE key = $1", key,
+	).Scan(&existing.Key, &existing.RequestHash, &songID, &existing.ReservedAt)
+	if err == sql.ErrNoRows {
+		// Ключ освободили между вставкой и чтением - пробуем занять снова
+		return r.Reserve(key, requestHash, reserveTimeout, ttl)
+	} else if err != nil {
+		return nil, err
+	}
+	existing.SongID = songID.String
+	return &existing, ErrIdempotencyKeyExists
+}
+
+// Complete связывает ключ, занятый в reservedAt, с добавленной песней.
+// Если ключ с тех пор был занят заново, запись не изменяется.
+func (r *IdempotencyRepository) Complete(key string, reservedAt time.Time, songID string) error {
+	_, err := r.db.Exec(
+		"UPDATE idempotency_keys SET song_id = $3 WHERE key = $1 AND created_at = $2 AND song_id IS NULL",
+		key, reservedAt, songID,
+	)
+	return err
+}
+
+// Release освобождает ключ, занятый в reservedAt, если запрос завершился ошибкой,
+// чтобы его можно было повторить.
+func (r *IdempotencyRepository) Release(key string, reservedAt time.Time) error {
+	_, err := r.db.Exec(
+		"DELETE FROM idempotency_keys WHERE key = $1 AND created_at = $2 AND song_id IS NULL",
+		key, reservedAt,
+	)
+	return err
+}
+
+// DeleteExpired удаляет ключи, занятые раньше, чем ttl назад, и возвращает
+// количество удалённых ключей.
+func (r *IdempotencyRepository) DeleteExpired(ttl time.Duration) (int64, error) {
+	res, err := r.db.Exec(
+		"DELETE FROM idempotency_keys WHERE created_at < now() - $1 * interval '1 millisecond'",
+		ttl.Milliseconds(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
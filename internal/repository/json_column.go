@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"music-test-lib/internal/domain"
+)
+
+// jsonColumn считывает значение столбца jsonb в dst. NULL оставляет dst без изменений.
+type jsonColumn struct {
+	dst any
+}
+
+func (c jsonColumn) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c.dst)
+	case string:
+		return json.Unmarshal([]byte(v), c.dst)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, c.dst)
+	}
+}
+
+// sourcesJSON возвращает источники полей песни в виде объекта JSON для столбца sources.
+func sourcesJSON(sources map[string]string) string {
+	if len(sources) == 0 {
+		return "{}"
+	}
+	data, _ := json.Marshal(sources)
+	return string(data)
+}
+
+// stanzasJSON возвращает строфы песни для столбца stanzas
+// или nil (NULL), если текст песни не разобран.
+func stanzasJSON(stanzas []domain.Stanza) any {
+	if stanzas == nil {
+		return nil
+	}
+	data, _ := json.Marshal(stanzas)
+	return string(data)
+}
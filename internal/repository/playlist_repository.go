@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"music-test-lib/internal/domain"
+)
+
+var (
+	ErrPlaylistNotFound  = errors.New("playlist not found")
+	ErrPlaylistForbidden = errors.New("playlist belongs to another user")
+	ErrPlaylistFull      = errors.New("playlist is full")
+	ErrEntryNotFound     = errors.New("playlist entry not found")
+	ErrInvalidPosition   = errors.New("invalid playlist position")
+)
+
+// MaxPlaylistEntries - максимальное количество записей в одном плейлисте.
+const MaxPlaylistEntries = 5000
+
+// PlaylistInput - данные плейлиста при добавлении и изменении.
+type PlaylistInput struct {
+	Name        string
+	Description string
+	Public      bool
+}
+
+// PlaylistFilter описывает условия отбора плейлистов.
+type PlaylistFilter struct {
+	// Пользователь, запрашивающий список: кроме публичных плейлистов
+	// ему доступны его собственные. Пустой - только публичные
+	Viewer string
+	// Владелец плейлистов; пустой - любой
+	Owner string
+}
+
+// PlaylistRepository хранит плейлисты и их записи.
+// Изменения записей плейлиста выполняются в транзакции с блокировкой плейлиста,
+// поэтому одновременные изменения одного плейлиста выполняются по очереди
+// и не нарушают порядок записей.
+type PlaylistRepository struct {
+	db *sqlx.DB
+}
+
+// NewPlaylistRepository создает новый PlaylistRepository.
+func NewPlaylistRepository(db *sqlx.DB) *PlaylistRepository {
+	return &PlaylistRepository{db: db}
+}
+
+// playlistColumns - столбцы плейлиста в порядке, ожидаемом scanPlaylist.
+const playlistColumns = "p.id, p.name, p.description, p.owner, p.is_public, " +
+	"(SELECT count(*) FROM playlist_entries e WHERE e.playlist_id = p.id), p.created_at, p.updated_at"
+
+func scanPlaylist(row rowScanner) (domain.Playlist, error) {
+	var p domain.Playlist
+	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.Owner, &p.Public, &p.EntryCount, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// GetPlaylists возвращает страницу доступных плейлистов, начиная с недавно
+// изменённых, и общее количество таких плейлистов.
+func (r *PlaylistRepository) GetPlaylists(filter PlaylistFilter, page Pagination) ([]domain.Playlist, int, error) {
+	var b queryBuilder
+	if filter.Viewer != "" {
+		b.where("(p.is_public OR p.owner = " + b.arg(filter.Viewer) + ")")
+	} else {
+		b.where("p.is_public")
+	}
+	if filter.Owner != "" {
+		b.where("p.owner = " + b.arg(filter.Owner))
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT count(*) FROM playlists p"+b.whereClause(), b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + playlistColumns + " FROM playlists p" + b.whereClause() +
+		" ORDER BY p.updated_at DESC, p.id DESC LIMIT " + b.arg(page.Limit) + " OFFSET " + b.arg(page.Offset)
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	playlists := []domain.Playlist{}
+	for rows.Next() {
+		p, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		playlists = append(playlists, p)
+	}
+	return playlists, total, rows.Err()
+}
+
+// GetPlaylistByID возвращает плейлист с записями в порядке позиций.
+// Доступ к плейлисту не проверяется.
+func (r *PlaylistRepository) GetPlaylistByID(id string) (*domain.Playlist, error) {
+	return getPlaylist(r.db, id)
+}
+
+// AddPlaylist добавляет пустой плейлист пользователя owner.
+func (r *PlaylistRepository) AddPlaylist(owner string, in PlaylistInput) (*domain.Playlist, error) {
+	var id string
+	err := r.db.QueryRow(
+		"INSERT INTO playlists (name, description, owner, is_public) VALUES ($1, $2, $3, $4) RETURNING id",
+		in.Name, in.Description, owner, in.Public,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPlaylistByID(id)
+}
+
+// UpdatePlaylist заменяет название, описание и видимость плейлиста.
+func (r *PlaylistRepository) UpdatePlaylist(id, user string, in PlaylistInput) (*domain.Playlist, error) {
+	return r.edit(id, user, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(
+			"UPDATE playlists SET name = $2, description = $3, is_public = $4 WHERE id = $1",
+			id, in.Name, in.Description, in.Public,
+		)
+		return err
+	})
+}
+
+// DeletePlaylist удаляет плейлист вместе с записями.
+func (r *PlaylistRepository) DeletePlaylist(id, user string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPlaylist(tx, id, user); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM playlists WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddEntry добавляет песню в плейлист на позицию position (начиная с 1);
+// position 0 - в конец. Записи с этой позиции сдвигаются вниз.
+func (r *PlaylistRepository) AddEntry(id, user, songID string, position int) (*domain.Playlist, error) {
+	return r.edit(id, user, func(tx *sqlx.Tx) error {
+		entries, err := entryIDs(tx, id)
+		if err != nil {
+			return err
+		}
+		if len(entries) >= MaxPlaylistEntries {
+			return ErrPlaylistFull
+		}
+		if position == 0 {
+			position = len(entries) + 1
+		}
+		if position < 1 || position > len(entries)+1 {
+			return ErrInvalidPosition
+		}
+
+		var entryID string
+		err = tx.QueryRow(
+			"INSERT INTO playlist_entries (playlist_id, song_id, position) "+
+				"SELECT $1::int, $2::int, COALESCE(max(position), 0) + 1 FROM playlist_entries WHERE playlist_id = $1 RETURNING id",
+			id, songID,
+		).Scan(&entryID)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return writePositions(tx, id, insertAt(entries, position-1, entryID))
+	})
+}
+
+// RemoveEntry удаляет запись из плейлиста; следующие записи сдвигаются вверх.
+func (r *PlaylistRepository) RemoveEntry(id, user, entryID string) (*domain.Playlist, error) {
+	return r.edit(id, user, func(tx *sqlx.Tx) error {
+		res, err := tx.Exec("DELETE FROM playlist_entries WHERE id = $1 AND playlist_id = $2", entryID, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrEntryNotFound
+		}
+		entries, err := entryIDs(tx, id)
+		if err != nil {
+			return err
+		}
+		return writePositions(tx, id, entries)
+	})
+}
+
+// MoveEntry переносит запись плейлиста на позицию position (начиная с 1),
+// сдвигая записи между старой и новой позицией.
+func (r *PlaylistRepository) MoveEntry(id, user, entryID string, position int) (*domain.Playlist, error) {
+	return r.edit(id, user, func(tx *sqlx.Tx) error {
+		entries, err := entryIDs(tx, id)
+		if err != nil {
+			return err
+		}
+		moved, err := moveEntry(entries, entryID, position)
+		if err != nil {
+			return err
+		}
+		return writePositions(tx, id, moved)
+	})
+}
+
+// DuplicatePlaylist создаёт копию плейлиста с записями в том же порядке.
+// Копия принадлежит пользователю owner и не видна другим пользователям.
+// Скопировать можно свой или публичный плейлист.
+func (r *PlaylistRepository) DuplicatePlaylist(id, owner, name string) (*domain.Playlist, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Плейлист блокируется для чтения, чтобы скопировать согласованный набор записей
+	var source domain.Playlist
+	err = tx.QueryRow("SELECT name, description, owner, is_public FROM playlists WHERE id = $1 FOR SHARE", id).
+		Scan(&source.Name, &source.Description, &source.Owner, &source.Public)
+	if err == sql.ErrNoRows || (err == nil && !source.Public && source.Owner != owner) {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = source.Name
+	}
+
+	var copyID string
+	err = tx.QueryRow(
+		"INSERT INTO playlists (name, description, owner, is_public) VALUES ($1, $2, $3, false) RETURNING id",
+		name, source.Description, owner,
+	).Scan(&copyID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO playlist_entries (playlist_id, song_id, position) "+
+			"SELECT $1::int, song_id, row_number() OVER (ORDER BY position, id) FROM playlist_entries WHERE playlist_id = $2",
+		copyID, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetPlaylistByID(copyID)
+}
+
+// edit выполняет изменение плейлиста в транзакции: блокирует плейлист,
+// проверяет, что он принадлежит пользователю user, вызывает change,
+// обновляет время изменения и возвращает изменённый плейлист.
+func (r *PlaylistRepository) edit(id, user string, change func(tx *sqlx.Tx) error) (*domain.Playlist, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPlaylist(tx, id, user); err != nil {
+		return nil, err
+	}
+	if err := change(tx); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("UPDATE playlists SET updated_at = now() WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+	playlist, err := getPlaylist(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// lockPlaylist блокирует плейлист до конца транзакции и проверяет, что он
+// принадлежит пользователю user. Чужой публичный плейлист - ErrPlaylistForbidden,
+// чужой приватный - ErrPlaylistNotFound, чтобы не раскрывать его существование.
+func lockPlaylist(tx *sqlx.Tx, id, user string) error {
+	var owner string
+	var public bool
+	err := tx.QueryRow("SELECT owner, is_public FROM playlists WHERE id = $1 FOR UPDATE", id).Scan(&owner, &public)
+	if err == sql.ErrNoRows {
+		return ErrPlaylistNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if owner != user {
+		if public {
+			return ErrPlaylistForbidden
+		}
+		return ErrPlaylistNotFound
+	}
+	return nil
+}
+
+// entryIDs возвращает ID записей плейлиста в порядке позиций.
+func entryIDs(tx *sqlx.Tx, id string) ([]string, error) {
+	var ids []string
+	err := tx.Select(&ids, "SELECT id FROM playlist_entries WHERE playlist_id = $1 ORDER BY position, id", id)
+	return ids, err
+}
+
+// writePositions присваивает записям плейлиста позиции 1..n в порядке ids.
+// Изменяются только записи, позиция которых отличается.
+func writePositions(tx *sqlx.Tx, id string, ids []string) error {
+	positions := make([]int64, len(ids))
+	for i := range ids {
+		positions[i] = int64(i + 1)
+	}
+	_, err := tx.Exec(
+		"UPDATE playlist_entries e SET position = v.position "+
+			"FROM unnest($2::int[], $3::int[]) AS v(id, position) "+
+			"WHERE e.id = v.id AND e.playlist_id = $1 AND e.position <> v.position",
+		id, pq.Array(ids), pq.Array(positions),
+	)
+	return err
+}
+
+// insertAt возвращает ids со значением id, вставленным по индексу i.
+func insertAt(ids []string, i int, id string) []string {
+	result := make([]string, 0, len(ids)+1)
+	result = append(result, ids[:i]...)
+	result = append(result, id)
+	return append(result, ids[i:]...)
+}
+
+// moveEntry возвращает ids, в котором entryID перенесён на позицию position
+// (начиная с 1). ids не изменяется.
+func moveEntry(ids []string, entryID string, position int) ([]string, error) {
+	from := -1
+	for i, e := range ids {
+		if e == entryID {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return nil, ErrEntryNotFound
+	}
+	if position < 1 || position > len(ids) {
+		return nil, ErrInvalidPosition
+	}
+	rest := append(ids[:from:from], ids[from+1:]...)
+	return insertAt(rest, position-1, entryID), nil
+}
+
+// getPlaylist читает плейлист с записями. Позиции записей вычисляются
+// по порядку: после удаления песен из библиотеки в хранимых позициях
+// могут быть пропуски.
+func getPlaylist(q sqlx.Queryer, id string) (*domain.Playlist, error) {
+	playlist, err := scanPlaylist(q.QueryRowx("SELECT "+playlistColumns+" FROM playlists p WHERE p.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(
+		"SELECT e.id, row_number() OVER (ORDER BY e.position, e.id), s.id, s.group_name, s.song_name, e.added_at "+
+			"FROM playlist_entries e JOIN songs s ON s.id = e.song_id "+
+			"WHERE e.playlist_id = $1 ORDER BY e.position, e.id", id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	playlist.Entries = []domain.PlaylistEntry{}
+	for rows.Next() {
+		var e domain.PlaylistEntry
+		if err := rows.Scan(&e.ID, &e.Position, &e.SongID, &e.Group, &e.Title, &e.AddedAt); err != nil {
+			return nil, err
+		}
+		playlist.Entries = append(playlist.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
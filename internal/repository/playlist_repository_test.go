@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInsertAt(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []string
+		i    int
+		want []string
+	}{
+		{"empty", nil, 0, []string{"x"}},
+		{"first", []string{"a", "b", "c"}, 0, []string{"x", "a", "b", "c"}},
+		{"middle", []string{"a", "b", "c"}, 1, []string{"a", "x", "b", "c"}},
+		{"last", []string{"a", "b", "c"}, 3, []string{"a", "b", "c", "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := append([]string(nil), tt.ids...)
+			if got := insertAt(tt.ids, tt.i, "x"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("insertAt(%v, %d) = %v, want %v", tt.ids, tt.i, got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.ids, ids) {
+				t.Errorf("insertAt changed its argument: %v", tt.ids)
+			}
+		})
+	}
+}
+
+func TestMoveEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		entryID  string
+		position int
+		want     []string
+		wantErr  error
+	}{
+		{name: "first to last", entryID: "a", position: 4, want: []string{"b", "c", "d", "a"}},
+		{name: "last to first", entryID: "d", position: 1, want: []string{"d", "a", "b", "c"}},
+		{name: "down", entryID: "b", position: 3, want: []string{"a", "c", "b", "d"}},
+		{name: "up", entryID: "c", position: 2, want: []string{"a", "c", "b", "d"}},
+		{name: "same position", entryID: "b", position: 2, want: []string{"a", "b", "c", "d"}},
+		{name: "position zero", entryID: "b", position: 0, wantErr: ErrInvalidPosition},
+		{name: "after the end", entryID: "b", position: 5, wantErr: ErrInvalidPosition},
+		{name: "unknown entry", entryID: "x", position: 1, wantErr: ErrEntryNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []string{"a", "b", "c", "d"}
+			got, err := moveEntry(ids, tt.entryID, tt.position)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("moveEntry(%q, %d) error = %v, want %v", tt.entryID, tt.position, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("moveEntry(%q, %d) = %v, want %v", tt.entryID, tt.position, got, tt.want)
+			}
+			if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(ids, want) {
+				t.Errorf("moveEntry changed its argument: %v", ids)
+			}
+		})
+	}
+}
+
+func TestMoveEntrySingle(t *testing.T) {
+	got, err := moveEntry([]string{"a"}, "a", 1)
+	if err != nil || !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("moveEntry([a], a, 1) = %v, %v, want [a]", got, err)
+	}
+}
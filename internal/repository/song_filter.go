@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"music-test-lib/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMatchMode = errors.New("invalid match mode")
+
+// MatchMode определяет способ сравнения строкового поля с образцом.
+type MatchMode string
+
+const (
+	// MatchContains - поле содержит образец (регистр не учитывается).
+	MatchContains MatchMode = "contains"
+	// MatchPrefix - поле начинается с образца (регистр не учитывается).
+	MatchPrefix MatchMode = "prefix"
+	// MatchExact - точное совпадение поля с образцом.
+	MatchExact MatchMode = "exact"
+)
+
+// ParseMatchMode разбирает режим сравнения. Пустая строка означает MatchContains.
+func ParseMatchMode(s string) (MatchMode, error) {
+	switch mode := MatchMode(strings.ToLower(s)); mode {
+	case "":
+		return MatchContains, nil
+	case MatchContains, MatchPrefix, MatchExact:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidMatchMode, s)
+	}
+}
+
+// StringFilter - условие на строковое поле песни.
+type StringFilter struct {
+	Value string
+	Mode  MatchMode
+}
+
+// DateLayout - формат дат в фильтрах и в базе данных.
+const DateLayout = "2006-01-02"
+
+// SongFilter описывает условия отбора песен.
+// Пустые (нулевые) поля не участвуют в фильтрации.
+type SongFilter struct {
+	// Полнотекстовый запрос по группе, названию и тексту песни
+	// в синтаксисе websearch_to_tsquery
+	Query string
+
+	Group  StringFilter
+	Title  StringFilter
+	Lyrics StringFilter
+	// Нечёткое сравнение группы и названия по триграммам (pg_trgm)
+	// вместо режимов сравнения Group.Mode и Title.Mode
+	Fuzzy bool
+
+	// Точная дата релиза
+	ReleaseDate time.Time
+	// Диапазон дат релиза, границы включаются
+	ReleaseDateFrom time.Time
+	ReleaseDateTo   time.Time
+	// Год релиза, например 1997
+	ReleaseYear int
+	// Десятилетие релиза, задаётся первым годом, например 1990
+	Decade int
+
+	// ID исполнителя
+	ArtistID string
+	// ID альбома, в который входит песня
+	AlbumID string
+	// ID исполнителя, участвующего в песне, и его роль. Если роль не задана,
+	// подходит участие в любой роли, если не задан исполнитель - любой
+	// исполнитель в этой роли
+	CreditArtistID string
+	CreditRole     domain.CreditRole
+
+	// Жанры (ID или названия): песня подходит, если у неё есть любой из них
+	Genres []string
+	// Учитывать также поджанры жанров Genres на любой глубине
+	IncludeSubgenres bool
+	// Теги: песня подходит, если у неё есть хотя бы один из них,
+	// а при AllTags - все теги
+	Tags    []string
+	AllTags bool
+}
+
+// queryBuilder собирает условия WHERE с позиционными параметрами ($1, $2, ...).
+type queryBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg добавляет значение в список параметров и возвращает его плейсхолдер.
+func (b *queryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// where добавляет условие. Плейсхолдеры в условии должны быть получены через arg.
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+// whereClause возвращает " WHERE ..." или пустую строку, если условий нет.
+func (b *queryBuilder) whereClause() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+// match добавляет условие на строковый столбец в соответствии с режимом сравнения.
+func (b *queryBuilder) match(column string, f StringFilter) {
+	if f.Value == "" {
+		return
+	}
+	switch f.Mode {
+	case MatchExact:
+		b.where(column + " = " + b.arg(f.Value))
+	case MatchPrefix:
+		b.where(column + " ILIKE " + b.arg(escapeLike(f.Value)+"%"))
+	default:
+		b.where(column + " ILIKE " + b.arg("%"+escapeLike(f.Value)+"%"))
+	}
+}
+
+// fuzzy сообщает, выполняется ли нечёткий поиск по группе или названию.
+func (f SongFilter) fuzzy() bool {
+	return f.Fuzzy && (f.Group.Value != "" || f.Title.Value != "")
+}
+
+// from возвращает источник строк для выборки по фильтру.
+// При полнотекстовом поиске к таблице присоединяется запрос query,
+// при нечётком - оценка сходства fuzzy.similarity; на них могут ссылаться
+// условия, сортировка и выбираемые столбцы.
+func (f SongFilter) from(b *queryBuilder) string {
+	from := "songs"
+	if f.Query != "" {
+		from += ", websearch_to_tsquery('simple', " + b.arg(f.Query) + ") AS query"
+	}
+	if f.fuzzy() {
+		// Сходство - среднее по заданным полям
+		var terms []string
+		if f.Group.Value != "" {
+			terms = append(terms, "similarity(group_name, "+b.arg(f.Group.Value)+")")
+		}
+		if f.Title.Value != "" {
+			terms = append(terms, "similarity(song_name, "+b.arg(f.Title.Value)+")")
+		}
+		from += fmt.Sprintf(", LATERAL (SELECT (%s) / %d AS similarity) AS fuzzy",
+			strings.Join(terms, " + "), len(terms))
+	}
+	return from
+}
+
+// apply добавляет в запрос условия фильтра.
+// Должен вызываться после from.
+func (f SongFilter) apply(b *queryBuilder) {
+	if f.Query != "" {
+		b.where("search_vector @@ query")
+	}
+	if f.fuzzy() {
+		b.similar("group_name", f.Group.Value)
+		b.similar("song_name", f.Title.Value)
+	} else {
+		b.match("group_name", f.Group)
+		b.match("song_name", f.Title)
+	}
+	b.match("lyrics", f.Lyrics)
+	if !f.ReleaseDate.IsZero() {
+		b.where("release_date = " + b.arg(f.ReleaseDate.Format(DateLayout)))
+	}
+	if !f.ReleaseDateFrom.IsZero() {
+		b.where("release_date >= " + b.arg(f.ReleaseDateFrom.Format(DateLayout)))
+	}
+	if !f.ReleaseDateTo.IsZero() {
+		b.where("release_date <= " + b.arg(f.ReleaseDateTo.Format(DateLayout)))
+	}
+	if f.ReleaseYear != 0 {
+		b.dateRange("release_date", yearStart(f.ReleaseYear), yearStart(f.ReleaseYear+1))
+	}
+	if f.Decade != 0 {
+		b.dateRange("release_date", yearStart(f.Decade), yearStart(f.Decade+10))
+	}
+	if f.ArtistID != "" {
+		b.where("artist_id = " + b.arg(f.ArtistID))
+	}
+	if f.AlbumID != "" {
+		b.where("EXISTS (SELECT 1 FROM album_tracks t WHERE t.song_id = songs.id AND t.album_id = " + b.arg(f.AlbumID) + ")")
+	}
+	f.applyCredit(b)
+	f.applyGenres(b)
+	f.applyTags(b)
+}
+
+// applyGenres добавляет условие на жанры песни.
+func (f SongFilter) applyGenres(b *queryBuilder) {
+	if len(f.Genres) == 0 {
+		return
+	}
+	var ids, names []string
+	for _, g := range f.Genres {
+		if _, err := strconv.ParseInt(g, 10, 32); err == nil {
+			ids = append(ids, g)
+		} else {
+			names = append(names, g)
+		}
+	}
+	genres := "SELECT id FROM genres WHERE id = ANY(" + b.arg(pq.Array(ids)) + "::int[]) " +
+		"OR normalize_name(name) IN (SELECT normalize_name(n) FROM unnest(" + b.arg(pq.Array(names)) + "::text[]) n)"
+	if f.IncludeSubgenres {
+		genres = "WITH RECURSIVE sub AS (" + genres +
+			" UNION SELECT g.id FROM genres g JOIN sub ON g.parent_id = sub.id) SELECT id FROM sub"
+	}
+	b.where("EXISTS (SELECT 1 FROM song_genres sg WHERE sg.song_id = songs.id AND sg.genre_id IN (" + genres + "))")
+}
+
+// applyTags добавляет условие на теги песни. Теги сравниваются
+// без учёта регистра и лишних пробелов.
+func (f SongFilter) applyTags(b *queryBuilder) {
+	if len(f.Tags) == 0 {
+		return
+	}
+	const hasTag = "EXISTS (SELECT 1 FROM song_tags st JOIN tags t ON t.id = st.tag_id " +
+		"WHERE st.song_id = songs.id AND normalize_name(t.name) "
+	if !f.AllTags {
+		b.where(hasTag + "IN (SELECT normalize_name(n) FROM unnest(" + b.arg(pq.Array(f.Tags)) + "::text[]) n))")
+		return
+	}
+	for _, tag := range f.Tags {
+		b.where(hasTag + "= normalize_name(" + b.arg(tag) + "))")
+	}
+}
+
+// applyCredit добавляет условие на участника песни. Основной исполнитель
+// песни хранится в songs.artist_id, остальные участники - в song_credits.
+func (f SongFilter) applyCredit(b *queryBuilder) {
+	if f.CreditArtistID == "" && f.CreditRole == "" {
+		return
+	}
+	primary := f.CreditRole == "" || f.CreditRole == domain.CreditPrimary
+	if primary && f.CreditArtistID == "" {
+		// Основной исполнитель есть у каждой песни
+		return
+	}
+
+	conds := []string{"c.song_id = songs.id"}
+	var artist string
+	if f.CreditArtistID != "" {
+		artist = b.arg(f.CreditArtistID)
+		conds = append(conds, "c.artist_id = "+artist)
+	}
+	if f.CreditRole != "" {
+		conds = append(conds, "c.role = "+b.arg(string(f.CreditRole)))
+	}
+	cond := "EXISTS (SELECT 1 FROM song_credits c WHERE " + strings.Join(conds, " AND ") + ")"
+	if primary {
+		cond = "(artist_id = " + artist + " OR " + cond + ")"
+	}
+	b.where(cond)
+}
+
+// similar добавляет условие триграммного сходства столбца со значением
+// (выше порога pg_trgm.similarity_threshold).
+func (b *queryBuilder) similar(column string, value string) {
+	if value == "" {
+		return
+	}
+	b.where(column + " % " + b.arg(value))
+}
+
+// dateRange добавляет условие from <= column < to.
+func (b *queryBuilder) dateRange(column string, from, to time.Time) {
+	b.where(column + " >= " + b.arg(from.Format(DateLayout)) +
+		" AND " + column + " < " + b.arg(to.Format(DateLayout)))
+}
+
+func yearStart(year int) time.Time {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// likeEscaper экранирует спецсимволы шаблона LIKE, чтобы они сравнивались буквально.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
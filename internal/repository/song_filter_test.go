@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"music-test-lib/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestSongFilterFuzzy(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    SongFilter
+		wantFrom  string
+		wantWhere string
+		wantArgs  []any
+	}{
+		{
+			name:      "group and title",
+			filter:    SongFilter{Fuzzy: true, Group: StringFilter{Value: "Metalica"}, Title: StringFilter{Value: "Nothing Els"}},
+			wantFrom:  "songs, LATERAL (SELECT (similarity(group_name, $1) + similarity(song_name, $2)) / 2 AS similarity) AS fuzzy",
+			wantWhere: " WHERE group_name % $3 AND song_name % $4",
+			wantArgs:  []any{"Metalica", "Nothing Els", "Metalica", "Nothing Els"},
+		},
+		{
+			name:      "title only",
+			filter:    SongFilter{Fuzzy: true, Title: StringFilter{Value: "Uprsing"}},
+			wantFrom:  "songs, LATERAL (SELECT (similarity(song_name, $1)) / 1 AS similarity) AS fuzzy",
+			wantWhere: " WHERE song_name % $2",
+			wantArgs:  []any{"Uprsing", "Uprsing"},
+		},
+		{
+			name:      "fuzzy ignores match mode",
+			filter:    SongFilter{Fuzzy: true, Group: StringFilter{Value: "Muse", Mode: MatchExact}},
+			wantFrom:  "songs, LATERAL (SELECT (similarity(group_name, $1)) / 1 AS similarity) AS fuzzy",
+			wantWhere: " WHERE group_name % $2",
+			wantArgs:  []any{"Muse", "Muse"},
+		},
+		{
+			name:      "without group and title",
+			filter:    SongFilter{Fuzzy: true, Lyrics: StringFilter{Value: "baby"}},
+			wantFrom:  "songs",
+			wantWhere: " WHERE lyrics ILIKE $1",
+			wantArgs:  []any{"%baby%"},
+		},
+		{
+			name:      "not fuzzy",
+			filter:    SongFilter{Group: StringFilter{Value: "Muse", Mode: MatchPrefix}},
+			wantFrom:  "songs",
+			wantWhere: " WHERE group_name ILIKE $1",
+			wantArgs:  []any{"Muse%"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b queryBuilder
+			if got := tt.filter.from(&b); got != tt.wantFrom {
+				t.Errorf("from = %q, want %q", got, tt.wantFrom)
+			}
+			tt.filter.apply(&b)
+			if got := b.whereClause(); got != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(b.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", b.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSimilaritySort(t *testing.T) {
+	fuzzy := SongFilter{Fuzzy: true, Group: StringFilter{Value: "Metalica"}}
+	tests := []struct {
+		name        string
+		filter      SongFilter
+		sort        []SortField
+		wantDefault []SortField
+		wantErr     bool
+	}{
+		{
+			name:        "fuzzy",
+			filter:      fuzzy,
+			sort:        []SortField{{Field: "similarity", Desc: true}},
+			wantDefault: []SortField{{Field: "similarity", Desc: true}},
+		},
+		{
+			name:        "fuzzy with full-text query",
+			filter:      SongFilter{Query: "baby", Fuzzy: true, Title: StringFilter{Value: "Supermasive"}},
+			sort:        []SortField{{Field: "similarity"}, {Field: "relevance", Desc: true}},
+			wantDefault: []SortField{{Field: "similarity", Desc: true}, {Field: "relevance", Desc: true}},
+		},
+		{
+			name:        "fuzzy flag without group and title",
+			filter:      SongFilter{Fuzzy: true},
+			sort:        []SortField{{Field: "similarity", Desc: true}},
+			wantDefault: []SortField{{Field: "id"}},
+			wantErr:     true,
+		},
+		{
+			name:        "not fuzzy",
+			filter:      SongFilter{Group: StringFilter{Value: "Muse"}},
+			sort:        []SortField{{Field: "similarity", Desc: true}},
+			wantDefault: []SortField{{Field: "id"}},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultSort(tt.filter); !reflect.DeepEqual(got, tt.wantDefault) {
+				t.Errorf("DefaultSort() = %v, want %v", got, tt.wantDefault)
+			}
+			err := checkSort(tt.sort, tt.filter)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidSort)) {
+				t.Errorf("checkSort(%v) error = %v, want error %v", tt.sort, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimilarityKeyset(t *testing.T) {
+	similarity := 0.75
+	item := domain.SongListItem{Song: domain.Song{ID: "7"}, Similarity: &similarity}
+	sort := []SortField{{Field: "similarity", Desc: true}}
+
+	key := SongKeyset(item, sort)
+	if want := []string{"0.75", "7"}; !reflect.DeepEqual(key.Values, want) {
+		t.Fatalf("SongKeyset() = %q, want %q", key.Values, want)
+	}
+	var b queryBuilder
+	if err := b.after(withTiebreaker(sort), key); err != nil {
+		t.Fatalf("after: %v", err)
+	}
+	if got, want := b.whereClause(), " WHERE ((fuzzy.similarity < $1) OR (fuzzy.similarity = $1 AND id > $2))"; got != want {
+		t.Errorf("where = %q, want %q", got, want)
+	}
+
+	// Песня без оценки сходства (выборка без нечёткого поиска)
+	item.Similarity = nil
+	if got := SongKeyset(item, sort).Values[0]; got != "0" {
+		t.Errorf("SongKeyset() without similarity = %q, want %q", got, "0")
+	}
+}
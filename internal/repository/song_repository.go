@@ -3,11 +3,35 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"music-test-lib/internal/domain"
+	"time"
 )
 
-var ErrNotFound = errors.New("song not found")
+var (
+	ErrNotFound        = errors.New("song not found")
+	ErrDuplicate       = errors.New("song already exists")
+	ErrVersionMismatch = errors.New("song version mismatch")
+)
+
+// uniqueViolation - код ошибки PostgreSQL при нарушении уникальности.
+const uniqueViolation = "23505"
+
+// DuplicateError сообщает, что песня с такими же группой и названием
+// (без учёта регистра и лишних пробелов) уже есть в библиотеке.
+type DuplicateError struct {
+	ExistingID string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: id %s", ErrDuplicate, e.ExistingID)
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
 
 // SongRepository определяет интерфейс для работы с песнями в базе данных.
 type SongRepository struct {
@@ -19,55 +43,199 @@ func NewSongRepository(db *sqlx.DB) *SongRepository {
 	return &SongRepository{db: db}
 }
 
-// GetSongs возвращает список песен с фильтрацией и пагинацией.
-func (r *SongRepository) GetSongs(params map[string][]string) ([]domain.Song, error) {
-	var songs []domain.Song
-	query := "SELECT id, group_name, song_name, lyrics, release_date, link FROM songs WHERE 1=1"
+// songColumns - столбцы песни в порядке, ожидаемом scanSong.
+const songColumns = "id, group_name, song_name, lyrics, " +
+	"COALESCE(to_char(release_date, 'YYYY-MM-DD'), ''), COALESCE(link, ''), artist_id, " +
+	"enrichment_status, COALESCE(enrichment_error, ''), sources, version, updated_at, " +
+	creditsColumn + ", " + genresColumn + ", " + tagsColumn
 
-	// Фильтрация по полям
-	if groupName, ok := params["group_name"]; ok && groupName[0] != "" {
-		query += " AND group_name ILIKE '%" + groupName[0] + "%'"
+// creditsColumn - участники песни в виде массива JSON: сначала основной
+// исполнитель песни (его название берётся из group_name, которое совпадает
+// с названием исполнителя), затем остальные участники в порядке ролей.
+const creditsColumn = "(SELECT json_agg(json_build_object('artist_id', c.artist_id, 'artist', c.artist, 'role', c.role) " +
+	"ORDER BY c.ord, c.position, c.artist) FROM (" +
+	"SELECT songs.artist_id::text AS artist_id, songs.group_name AS artist, 'primary' AS role, 0 AS ord, 0 AS position " +
+	"UNION ALL " +
+	"SELECT sc.artist_id::text, a.name, sc.role, " +
+	"array_position(ARRAY['primary', 'featured', 'composer', 'lyricist', 'producer'], sc.role::text), sc.position " +
+	"FROM song_credits sc JOIN artists a ON a.id = sc.artist_id " +
+	"WHERE sc.song_id = songs.id AND NOT (sc.role = 'primary' AND sc.artist_id = songs.artist_id)" +
+	") c)"
+
+// genresColumn и tagsColumn - жанры и теги песни в виде массивов JSON
+// в алфавитном порядке.
+const (
+	genresColumn = "(SELECT COALESCE(json_agg(json_build_object('id', g.id::text, 'name', g.name) ORDER BY lower(g.name)), '[]') " +
+		"FROM song_genres sg JOIN genres g ON g.id = sg.genre_id WHERE sg.song_id = songs.id)"
+	tagsColumn = "(SELECT COALESCE(json_agg(t.name ORDER BY lower(t.name)), '[]') " +
+		"FROM song_tags st JOIN tags t ON t.id = st.tag_id WHERE st.song_id = songs.id)"
+)
+
+// searchColumns - столбцы результатов полнотекстового поиска:
+// релевантность и фрагмент текста песни с выделенными совпадениями.
+const searchColumns = rankExpr + ", ts_headline('simple', lyrics, query, " +
+	"'StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MinWords=3, MaxWords=15, FragmentDelimiter=\" ... \"')"
+
+// Pagination задаёт окно выборки: не более Limit строк, начиная с Offset.
+// Если задан After, выборка начинается сразу после указанной позиции,
+// а Offset не учитывается.
+type Pagination struct {
+	Limit  int
+	Offset int
+	After  *Keyset
+}
+
+// Keyset - позиция строки в упорядоченной выборке:
+// значения полей сортировки (включая id) для этой строки.
+type Keyset struct {
+	Values []string
+}
+
+// SongKeyset возвращает позицию песни в выборке GetSongs с сортировкой sort.
+func SongKeyset(item domain.SongListItem, sort []SortField) Keyset {
+	sort = withTiebreaker(sort)
+	key := Keyset{Values: make([]string, len(sort))}
+	for i, f := range sort {
+		key.Values[i] = sortColumns[f.Field].value(item)
+	}
+	return key
+}
+
+// GetSongs возвращает страницу песен, удовлетворяющих фильтру, в порядке sort,
+// и общее количество подходящих под фильтр песен.
+// Без сортировки применяется DefaultSort.
+func (r *SongRepository) GetSongs(filter SongFilter, sort []SortField, page Pagination) ([]domain.SongListItem, int, error) {
+	if len(sort) == 0 {
+		sort = DefaultSort(filter)
+	}
+	if err := checkSort(sort, filter); err != nil {
+		return nil, 0, err
 	}
-	if songName, ok := params["song_name"]; ok && songName[0] != "" {
-		query += " AND song_name ILIKE '%" + songName[0] + "%'"
+	sort = withTiebreaker(sort)
+
+	var b queryBuilder
+	from := filter.from(&b)
+	filter.apply(&b)
+
+	// Общее количество записей для расчёта числа страниц
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM "+from+b.whereClause(), b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || (page.After == nil && page.Offset >= total) {
+		return []domain.SongListItem{}, total, nil
+	}
+
+	var window string
+	if page.After != nil {
+		if err := b.after(sort, *page.After); err != nil {
+			return nil, 0, err
+		}
+		window = " LIMIT " + b.arg(page.Limit)
+	} else {
+		window = " LIMIT " + b.arg(page.Limit) + " OFFSET " + b.arg(page.Offset)
 	}
-	if releaseDate, ok := params["release_date"]; ok && releaseDate[0] != "" {
-		query += " AND release_date = '" + releaseDate[0] + "'"
+	columns := songColumns
+	if filter.Query != "" {
+		columns += ", " + searchColumns
 	}
-	if lyrics, ok := params["lyrics"]; ok && lyrics[0] != "" {
-		query += " AND lyrics ILIKE '%" + lyrics[0] + "%'"
+	if filter.fuzzy() {
+		columns += ", fuzzy.similarity"
 	}
+	query := "SELECT " + columns + " FROM " + from + b.whereClause() + orderBy(sort) + window
 
-	// Выполнение запроса
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, b.args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	// Чтение результатов
+	items := []domain.SongListItem{}
 	for rows.Next() {
-		var song domain.Song
-		if err := rows.Scan(
-			&song.ID,
-			&song.Group,
-			&song.Title,
-			&song.Lyrics,
-			&song.ReleaseDate,
-			&song.Link,
-		); err != nil {
-			return nil, err
+		var item domain.SongListItem
+		dest := songDest(&item.Song)
+		if filter.Query != "" {
+			item.Rank = new(float64)
+			dest = append(dest, item.Rank, &item.Snippet)
 		}
-		songs = append(songs, song)
+		if filter.fuzzy() {
+			item.Similarity = new(float64)
+			dest = append(dest, item.Similarity)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// SuggestSong возвращает наиболее похожие на group и title названия группы
+// и песни из библиотеки. Пустые значения не ищутся; если похожих названий нет,
+// соответствующее поле результата пустое.
+func (r *SongRepository) SuggestSong(group, title string) (domain.SongSuggestion, error) {
+	var suggestion domain.SongSuggestion
+	fields := []struct {
+		column string
+		value  string
+		dst    *string
+	}{
+		{"group_name", group, &suggestion.Group},
+		{"song_name", title, &suggestion.Title},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		query := "SELECT " + f.column + " FROM songs WHERE " + f.column + " % $1" +
+			" ORDER BY similarity(" + f.column + ", $1) DESC, " + f.column + " LIMIT 1"
+		err := r.db.QueryRow(query, f.value).Scan(f.dst)
+		if err != nil && err != sql.ErrNoRows {
+			return suggestion, err
+		}
+	}
+	return suggestion, nil
+}
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// songDest возвращает приёмники для столбцов songColumns.
+func songDest(song *domain.Song) []any {
+	return []any{
+		&song.ID,
+		&song.Group,
+		&song.Title,
+		&song.Lyrics,
+		&song.ReleaseDate,
+		&song.Link,
+		&song.ArtistID,
+		&song.EnrichmentStatus,
+		&song.EnrichmentError,
+		jsonColumn{&song.Sources},
+		&song.Version,
+		&song.UpdatedAt,
+		jsonColumn{&song.Credits},
+		jsonColumn{&song.Genres},
+		jsonColumn{&song.Tags},
 	}
-	return songs, nil
+}
+
+// scanSong читает песню из строки, выбранной со столбцами songColumns.
+func scanSong(row rowScanner) (domain.Song, error) {
+	var song domain.Song
+	err := row.Scan(songDest(&song)...)
+	return song, err
 }
 
 // GetSongByID возвращает текст песни по её ID.
 func (r *SongRepository) GetSongByID(id string) (*domain.Song, error) {
-	var song domain.Song
-	query := "SELECT id, group_name, song_name, release_date, lyrics, link FROM songs WHERE id = $1"
-	err := r.db.QueryRow(query, id).Scan(&song.ID, &song.Group, &song.Title, &song.ReleaseDate, &song.Lyrics, &song.Link)
+	song, err := scanSong(r.db.QueryRow("SELECT "+songColumns+" FROM songs WHERE id = $1", id))
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	} else if err != nil {
@@ -76,27 +244,434 @@ func (r *SongRepository) GetSongByID(id string) (*domain.Song, error) {
 	return &song, nil
 }
 
-// AddSong добавляет новую песню в базу данных.
-func (r *SongRepository) AddSong(song domain.SongWithoutID) error {
-	_, err := r.db.Exec(
-		"INSERT INTO songs (group_name, song_name, release_date, lyrics, link) VALUES ($1, $2, $3, $4, $5)",
-		song.Group, song.Title, song.ReleaseDate, song.Lyrics, song.Link,
-	)
+// GetSongStanzas возвращает песню по её ID вместе со строфами текста.
+// Если текст ещё не разобран на строфы, Stanzas равно nil.
+func (r *SongRepository) GetSongStanzas(id string) (*domain.Song, error) {
+	var song domain.Song
+	err := r.db.QueryRow("SELECT "+songColumns+", stanzas FROM songs WHERE id = $1", id).
+		Scan(append(songDest(&song), jsonColumn{&song.Stanzas})...)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
 
+// SaveStanzas сохраняет строфы текста песни, если текст не изменился после разбора.
+func (r *SongRepository) SaveStanzas(id, lyrics string, stanzas []domain.Stanza) error {
+	_, err := r.db.Exec("UPDATE songs SET stanzas = $3 WHERE id = $1 AND lyrics = $2", id, lyrics, stanzasJSON(stanzas))
 	return err
 }
 
-// UpdateSong обновляет данные песни.
-func (r *SongRepository) UpdateSong(song domain.Song) error {
-	_, err := r.db.Exec(
-		"UPDATE songs SET group_name= $2, song_name = $3, release_date = $4, lyrics = $5, link = $6 WHERE id = $1",
-		song.ID, song.Group, song.Title, song.ReleaseDate, song.Lyrics, song.Link,
-	)
+// FindSongByName возвращает ID песни с такими же группой и названием
+// без учёта регистра и лишних пробелов.
+func (r *SongRepository) FindSongByName(group, title string) (string, error) {
+	var id string
+	err := r.db.QueryRow(
+		"SELECT id FROM songs WHERE normalize_name(group_name) = normalize_name($1) "+
+			"AND normalize_name(song_name) = normalize_name($2)",
+		group, title,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	return id, err
+}
+
+// AddSong добавляет новую песню в базу данных и возвращает её с присвоенным ID.
+// Если такая песня уже есть, возвращает *DuplicateError.
+func (r *SongRepository) AddSong(song domain.SongWithoutID) (*domain.Song, error) {
+	created, err := scanSong(r.db.QueryRow(
+		"INSERT INTO songs (group_name, song_name, release_date, lyrics, link, sources, stanzas) "+
+			"VALUES ($1, $2, NULLIF($3, '')::date, $4, $5, $6, $7) RETURNING "+songColumns,
+		song.Group, song.Title, song.ReleaseDate, song.Lyrics, song.Link, sourcesJSON(song.Sources), stanzasJSON(song.Stanzas),
+	))
+	if err != nil {
+		return nil, r.duplicateError(err, &song.Group, &song.Title, "")
+	}
+	return &created, nil
+}
+
+// AddPendingSong добавляет песню без данных из внешнего API в состоянии
+// EnrichmentPending и ставит задание на получение этих данных.
+// Если такая песня уже есть, возвращает *DuplicateError.
+func (r *SongRepository) AddPendingSong(group, title string) (*domain.Song, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created, err := scanSong(tx.QueryRow(
+		"INSERT INTO songs (group_name, song_name, lyrics, enrichment_status) VALUES ($1, $2, '', $3) "+
+			"RETURNING "+songColumns,
+		group, title, domain.EnrichmentPending,
+	))
+	if err != nil {
+		return nil, r.duplicateError(err, &group, &title, "")
+	}
+	if _, err := tx.Exec("INSERT INTO enrichment_jobs (song_id) VALUES ($1)", created.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// duplicateError преобразует нарушение уникальности группы и названия песни
+// в *DuplicateError с ID существующей песни. Если группа или название не
+// изменялись (nil), они берутся из песни с ID id. Прочие ошибки возвращаются как есть.
+func (r *SongRepository) duplicateError(err error, group, title *string, id string) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return err
+	}
+	if group == nil || title == nil {
+		current, getErr := r.GetSongByID(id)
+		if getErr != nil {
+			return err
+		}
+		if group == nil {
+			group = &current.Group
+		}
+		if title == nil {
+			title = &current.Title
+		}
+	}
+	existingID, findErr := r.FindSongByName(*group, *title)
+	if findErr != nil {
+		return err
+	}
+	return &DuplicateError{ExistingID: existingID}
+}
+
+// SongUpdate - изменения данных песни. Поля со значением nil не изменяются,
+// пустая строка в ReleaseDate или Link очищает поле.
+type SongUpdate struct {
+	Group       *string
+	Title       *string
+	ReleaseDate *string
+	Lyrics      *string
+	Link        *string
+	// Строфы изменённого текста; задаются вместе с Lyrics
+	Stanzas []domain.Stanza
+}
+
+// externalFields возвращает изменяемые поля, которые могли быть получены
+// из внешних источников: после изменения вручную их источник не указывается.
+func (u SongUpdate) externalFields() []string {
+	fields := []string{}
+	for name, value := range map[string]*string{
+		"release_date": u.ReleaseDate,
+		"lyrics":       u.Lyrics,
+		"link":         u.Link,
+	} {
+		if value != nil {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// UpdateSong атомарно применяет изменения к песне и возвращает обновлённую песню.
+// Если задан versions, песня изменяется, только если её текущая версия есть в списке,
+// иначе возвращается ErrVersionMismatch. Если песни нет, возвращает ErrNotFound,
+// если после изменения она совпадает с другой песней по группе и названию - *DuplicateError.
+func (r *SongRepository) UpdateSong(id string, upd SongUpdate, versions []int64) (*domain.Song, error) {
+	song, err := scanSong(r.db.QueryRow(
+		"UPDATE songs SET group_name = COALESCE($2, group_name), song_name = COALESCE($3, song_name), "+
+			"release_date = CASE WHEN $4::text IS NULL THEN release_date ELSE NULLIF($4, '')::date END, "+
+			"lyrics = COALESCE($5, lyrics), link = CASE WHEN $6::text IS NULL THEN link ELSE NULLIF($6, '') END, "+
+			"sources = sources - $7::text[], stanzas = COALESCE($8::jsonb, stanzas) "+
+			"WHERE id = $1 AND ($9::bigint[] IS NULL OR version = ANY($9)) RETURNING "+songColumns,
+		id, upd.Group, upd.Title, upd.ReleaseDate, upd.Lyrics, upd.Link, pq.Array(upd.externalFields()), stanzasJSON(upd.Stanzas),
+		pq.Array(versions),
+	))
+	if err == sql.ErrNoRows {
+		return nil, r.missingSongError(id, versions)
+	}
+	if err != nil {
+		return nil, r.duplicateError(err, upd.Group, upd.Title, id)
+	}
+	return &song, nil
+}
+
+// RefreshSong сохраняет данные песни, заново полученные из внешнего API.
+// Если данные песни ещё не были получены, задание на их получение удаляется.
+// Если песни нет, возвращает ErrNotFound.
+func (r *SongRepository) RefreshSong(id string, song domain.SongWithoutID) (*domain.Song, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	saved, err := saveExternalInfo(tx, id, song)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// TouchRefreshed отмечает, что данные песни проверены во внешнем API,
+// не изменяя их, чтобы песня не попадала в StaleSongs до следующего срока.
+func (r *SongRepository) TouchRefreshed(id string) error {
+	_, err := r.db.Exec("UPDATE songs SET refreshed_at = now() WHERE id = $1", id)
 	return err
 }
 
+// StaleSongs возвращает не более limit песен, данные которых получены
+// из внешнего API раньше, чем maxAge назад, начиная с самых старых.
+// Песни, данные которых ещё не получены или не получены из-за ошибки, не возвращаются.
+func (r *SongRepository) StaleSongs(maxAge time.Duration, limit int) ([]domain.Song, error) {
+	rows, err := r.db.Query(
+		"SELECT "+songColumns+" FROM songs "+
+			"WHERE enrichment_status = $1 AND (refreshed_at IS NULL OR refreshed_at < now() - $2 * interval '1 millisecond') "+
+			"ORDER BY refreshed_at NULLS FIRST, id LIMIT $3",
+		domain.EnrichmentReady, maxAge.Milliseconds(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var songs []domain.Song
+	for rows.Next() {
+		song, err := scanSong(rows)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
+
 // DeleteSong удаляет песню из базы данных.
-func (r *SongRepository) DeleteSong(id string) error {
-	_, err := r.db.Exec("DELETE FROM songs WHERE id = $1", id)
+// Если задан versions, песня удаляется, только если её текущая версия есть в списке,
+// иначе возвращается ErrVersionMismatch. Если песни нет, возвращает ErrNotFound.
+func (r *SongRepository) DeleteSong(id string, versions []int64) error {
+	res, err := r.db.Exec(
+		"DELETE FROM songs WHERE id = $1 AND ($2::bigint[] IS NULL OR version = ANY($2))",
+		id, pq.Array(versions),
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return r.missingSongError(id, versions)
+	}
+	return nil
+}
+
+// SetSongCredits заменяет участников песни и увеличивает её версию.
+// Основной исполнитель песни (из group_name) остаётся участником с ролью primary
+// независимо от credits. Если песни нет или её версия не входит в versions,
+// возвращает ErrNotFound или ErrVersionMismatch, если нет кого-то из исполнителей -
+// ErrArtistNotFound.
+func (r *SongRepository) SetSongCredits(id string, credits []domain.Credit, versions []int64) (*domain.Song, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	artistID, err := r.touchSong(tx, id, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM song_credits WHERE song_id = $1", id); err != nil {
+		return nil, err
+	}
+	var artistIDs, roles []string
+	var positions []int64
+	for i, c := range credits {
+		if c.Role == domain.CreditPrimary && c.ArtistID == artistID {
+			continue
+		}
+		artistIDs = append(artistIDs, c.ArtistID)
+		roles = append(roles, string(c.Role))
+		positions = append(positions, int64(i))
+	}
+	_, err = tx.Exec(
+		"INSERT INTO song_credits (song_id, artist_id, role, position) "+
+			"SELECT $1::int, * FROM unnest($2::int[], $3::text[], $4::int[])",
+		id, pq.Array(artistIDs), pq.Array(roles), pq.Array(positions),
+	)
+	if err != nil {
+		return nil, artistReferenceError(err)
+	}
+
+	return commitSong(tx, id)
+}
+
+// SetSongGenres заменяет жанры песни и увеличивает её версию.
+// Если какого-то жанра нет, возвращает ErrGenreNotFound.
+func (r *SongRepository) SetSongGenres(id string, genreIDs []string, versions []int64) (*domain.Song, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := r.touchSong(tx, id, versions); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM song_genres WHERE song_id = $1", id); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO song_genres (song_id, genre_id) SELECT $1::int, unnest($2::int[])",
+		id, pq.Array(genreIDs),
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return nil, ErrGenreNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return commitSong(tx, id)
+}
+
+// SetSongTags заменяет теги песни и увеличивает её версию.
+// Теги сравниваются без учёта регистра и лишних пробелов; отсутствующие теги создаются.
+func (r *SongRepository) SetSongTags(id string, tags []string, versions []int64) (*domain.Song, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := r.touchSong(tx, id, versions); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM song_tags WHERE song_id = $1", id); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT ((normalize_name(name))) DO NOTHING",
+		pq.Array(tags),
+	); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO song_tags (song_id, tag_id) "+
+			"SELECT $1::int, t.id FROM tags t WHERE normalize_name(t.name) IN (SELECT normalize_name(n) FROM unnest($2::text[]) n)",
+		id, pq.Array(tags),
+	); err != nil {
+		return nil, err
+	}
+	return commitSong(tx, id)
+}
+
+// touchSong увеличивает версию песни и обновляет время её изменения при изменении
+// связанных с песней данных (участников, жанров, тегов) и блокирует строку песни
+// до конца транзакции. Возвращает ID основного исполнителя песни.
+func (r *SongRepository) touchSong(tx *sqlx.Tx, id string, versions []int64) (artistID string, err error) {
+	err = tx.QueryRow(
+		"UPDATE songs SET version = version + 1, updated_at = now() "+
+			"WHERE id = $1 AND ($2::bigint[] IS NULL OR version = ANY($2)) RETURNING artist_id",
+		id, pq.Array(versions),
+	).Scan(&artistID)
+	if err == sql.ErrNoRows {
+		return "", r.missingSongError(id, versions)
+	}
+	return artistID, err
+}
+
+// touchLinkedSongs увеличивает версию и обновляет время изменения песен,
+// ID которых возвращает запрос songIDs, при изменении общих для них данных
+// (названия жанра, тега, исполнителя), чтобы их ETag перестали совпадать.
+func touchLinkedSongs(tx *sqlx.Tx, songIDs string, args ...any) error {
+	_, err := tx.Exec("UPDATE songs SET version = version + 1, updated_at = now() WHERE id IN ("+songIDs+")", args...)
 	return err
 }
+
+// commitSong читает песню в транзакции tx и фиксирует транзакцию.
+func commitSong(tx *sqlx.Tx, id string) (*domain.Song, error) {
+	song, err := scanSong(tx.QueryRow("SELECT "+songColumns+" FROM songs WHERE id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// missingSongError возвращает ошибку для песни, которую не удалось изменить
+// или удалить с условием на версию: ErrVersionMismatch, если песня есть, иначе ErrNotFound.
+func (r *SongRepository) missingSongError(id string, versions []int64) error {
+	if versions == nil {
+		return ErrNotFound
+	}
+	var exists bool
+	if err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1)", id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return ErrVersionMismatch
+	}
+	return ErrNotFound
+}
+
+// DeleteSongs удаляет песни с указанными ID и возвращает ID удалённых песен.
+// Песня, для ID которой в versions указана версия, удаляется, только если её
+// версия совпадает: ID песен, которые не удалены из-за другой версии,
+// возвращаются в mismatched.
+func (r *SongRepository) DeleteSongs(ids []int64, versions map[int64]int64) (deleted, mismatched []int64, err error) {
+	checkedIDs := make([]int64, 0, len(versions))
+	checkedVersions := make([]int64, 0, len(versions))
+	for id, version := range versions {
+		checkedIDs = append(checkedIDs, id)
+		checkedVersions = append(checkedVersions, version)
+	}
+
+	// Вторая часть запроса видит песни до удаления, поэтому удалённые исключаются явно
+	rows, err := r.db.Query(`
+		WITH checked (id, version) AS (SELECT * FROM unnest($2::bigint[], $3::bigint[])),
+		deleted AS (
+			DELETE FROM songs s
+			WHERE s.id = ANY($1::bigint[])
+			  AND NOT EXISTS (SELECT 1 FROM checked c WHERE c.id = s.id AND c.version <> s.version)
+			RETURNING s.id
+		)
+		SELECT id, true FROM deleted
+		UNION ALL
+		SELECT s.id, false FROM songs s JOIN checked c ON c.id = s.id
+		WHERE s.id = ANY($1::bigint[]) AND s.id NOT IN (SELECT id FROM deleted)`,
+		pq.Array(ids), pq.Array(checkedIDs), pq.Array(checkedVersions),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	deleted, mismatched = []int64{}, []int64{}
+	for rows.Next() {
+		var id int64
+		var isDeleted bool
+		if err := rows.Scan(&id, &isDeleted); err != nil {
+			return nil, nil, err
+		}
+		if isDeleted {
+			deleted = append(deleted, id)
+		} else {
+			mismatched = append(mismatched, id)
+		}
+	}
+	return deleted, mismatched, rows.Err()
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"music-test-lib/internal/domain"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortField - поле сортировки списка песен.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+func (f SortField) String() string {
+	if f.Desc {
+		return "-" + f.Field
+	}
+	return f.Field
+}
+
+// sortColumn описывает поле, по которому разрешена сортировка.
+type sortColumn struct {
+	// SQL-выражение для ORDER BY. NULL заменяются значением, чтобы
+	// порядок был полным и по нему можно было продолжать выборку.
+	expr string
+	// value возвращает значение выражения для песни.
+	value func(item domain.SongListItem) string
+	// available сообщает, применима ли сортировка к выборке с фильтром.
+	// nil означает, что сортировка применима всегда.
+	available func(f SongFilter) bool
+}
+
+// sortColumns - список полей, по которым разрешена сортировка.
+var sortColumns = map[string]sortColumn{
+	"id": {
+		expr:  "id",
+		value: func(s domain.SongListItem) string { return s.ID },
+	},
+	"group_name": {
+		expr:  "group_name",
+		value: func(s domain.SongListItem) string { return s.Group },
+	},
+	"song_name": {
+		expr:  "song_name",
+		value: func(s domain.SongListItem) string { return s.Title },
+	},
+	"release_date": {
+		expr: "COALESCE(release_date, 'infinity'::date)",
+		value: func(s domain.SongListItem) string {
+			if s.ReleaseDate == "" {
+				return "infinity"
+			}
+			return s.ReleaseDate
+		},
+	},
+	"link": {
+		expr:  "COALESCE(link, '')",
+		value: func(s domain.SongListItem) string { return s.Link },
+	},
+	"relevance": {
+		expr:      rankExpr,
+		value:     func(s domain.SongListItem) string { return formatScore(s.Rank) },
+		available: func(f SongFilter) bool { return f.Query != "" },
+	},
+	"similarity": {
+		expr:      "fuzzy.similarity",
+		value:     func(s domain.SongListItem) string { return formatScore(s.Similarity) },
+		available: SongFilter.fuzzy,
+	},
+}
+
+func formatScore(score *float64) string {
+	if score == nil {
+		return "0"
+	}
+	return strconv.FormatFloat(*score, 'g', -1, 64)
+}
+
+// rankExpr - релевантность песни полнотекстовому запросу query.
+const rankExpr = "ts_rank(search_vector, query)"
+
+// DefaultSort возвращает сортировку, применяемую к выборке с фильтром filter,
+// если сортировка не задана явно: по сходству при нечётком поиске,
+// по релевантности при полнотекстовом, иначе по id.
+func DefaultSort(filter SongFilter) []SortField {
+	var fields []SortField
+	if filter.fuzzy() {
+		fields = append(fields, SortField{Field: "similarity", Desc: true})
+	}
+	if filter.Query != "" {
+		fields = append(fields, SortField{Field: "relevance", Desc: true})
+	}
+	if len(fields) == 0 {
+		fields = append(fields, SortField{Field: "id"})
+	}
+	return fields
+}
+
+// ParseSort разбирает параметр сортировки вида "-release_date,group_name".
+// Минус перед названием поля означает сортировку по убыванию.
+// Поле relevance допустимо только вместе с полнотекстовым запросом,
+// similarity - только при нечётком поиске.
+func ParseSort(s string) ([]SortField, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var fields []SortField
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		f := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortColumns[f.Field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSort, f.Field)
+		}
+		if seen[f.Field] {
+			return nil, fmt.Errorf("%w: duplicate field %q", ErrInvalidSort, f.Field)
+		}
+		seen[f.Field] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// FormatSort возвращает параметр сортировки в каноническом виде.
+func FormatSort(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// withTiebreaker дополняет сортировку полем id, чтобы порядок строк был однозначным.
+func withTiebreaker(fields []SortField) []SortField {
+	for _, f := range fields {
+		if f.Field == "id" {
+			return fields
+		}
+	}
+	return append(fields[:len(fields):len(fields)], SortField{Field: "id"})
+}
+
+// orderBy возвращает выражение ORDER BY для сортировки.
+func orderBy(fields []SortField) string {
+	terms := make([]string, len(fields))
+	for i, f := range fields {
+		terms[i] = sortColumns[f.Field].expr
+		if f.Desc {
+			terms[i] += " DESC"
+		}
+	}
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
+// after добавляет условие, отбирающее строки, следующие за позицией key
+// при сортировке fields:
+// (a > $1) OR (a = $1 AND b > $2) OR ...
+func (b *queryBuilder) after(fields []SortField, key Keyset) error {
+	if len(key.Values) != len(fields) {
+		return fmt.Errorf("%w: keyset does not match sort", ErrInvalidSort)
+	}
+	var alternatives []string
+	var equal []string
+	for i, f := range fields {
+		expr := sortColumns[f.Field].expr
+		placeholder := b.arg(key.Values[i])
+		op := " > "
+		if f.Desc {
+			op = " < "
+		}
+		alternatives = append(alternatives, "("+strings.Join(append(equal, expr+op+placeholder), " AND ")+")")
+		equal = append(equal, expr+" = "+placeholder)
+	}
+	b.where("(" + strings.Join(alternatives, " OR ") + ")")
+	return nil
+}
+
+// checkSort проверяет, что сортировка применима к выборке с фильтром filter.
+func checkSort(fields []SortField, filter SongFilter) error {
+	for _, f := range fields {
+		if available := sortColumns[f.Field].available; available != nil && !available(filter) {
+			return fmt.Errorf("%w: %q is not available for this filter", ErrInvalidSort, f.Field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"music-test-lib/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []SortField
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"one field", "song_name", []SortField{{Field: "song_name"}}, false},
+		{"descending", "-release_date", []SortField{{Field: "release_date", Desc: true}}, false},
+		{"several fields with spaces", "-release_date, group_name", []SortField{
+			{Field: "release_date", Desc: true}, {Field: "group_name"},
+		}, false},
+		{"unknown field", "lyrics", nil, true},
+		{"sql injection", "id; DROP TABLE songs", nil, true},
+		{"duplicate field", "id,-id", nil, true},
+		{"empty field", "id,", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSort) {
+					t.Fatalf("ParseSort(%q) error = %v, want ErrInvalidSort", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			if formatted := FormatSort(got); len(got) > 0 {
+				again, err := ParseSort(formatted)
+				if err != nil || !reflect.DeepEqual(again, got) {
+					t.Errorf("ParseSort(FormatSort(%v)) = %v, %v", got, again, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTiebreaker(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []SortField
+		want []SortField
+	}{
+		{"empty", nil, []SortField{{Field: "id"}}},
+		{"without id", []SortField{{Field: "group_name"}}, []SortField{{Field: "group_name"}, {Field: "id"}}},
+		{"with id", []SortField{{Field: "id", Desc: true}, {Field: "song_name"}}, []SortField{{Field: "id", Desc: true}, {Field: "song_name"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := append([]SortField(nil), tt.in...)
+			if got := withTiebreaker(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withTiebreaker(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.in, in) {
+				t.Errorf("withTiebreaker changed its argument: %v", tt.in)
+			}
+		})
+	}
+}
+
+func TestSongKeysetAfter(t *testing.T) {
+	item := domain.SongListItem{Song: domain.Song{ID: "7", Group: "Muse", Title: "Uprising"}}
+	tests := []struct {
+		name      string
+		sort      []SortField
+		wantKey   []string
+		wantWhere string
+	}{
+		{
+			name:      "default",
+			sort:      nil,
+			wantKey:   []string{"7"},
+			wantWhere: " WHERE ((id > $1))",
+		},
+		{
+			name:      "descending with tiebreaker",
+			sort:      []SortField{{Field: "group_name", Desc: true}},
+			wantKey:   []string{"Muse", "7"},
+			wantWhere: " WHERE ((group_name < $1) OR (group_name = $1 AND id > $2))",
+		},
+		{
+			name:    "empty release date and link",
+			sort:    []SortField{{Field: "release_date"}, {Field: "link", Desc: true}},
+			wantKey: []string{"infinity", "", "7"},
+			wantWhere: " WHERE ((COALESCE(release_date, 'infinity'::date) > $1)" +
+				" OR (COALESCE(release_date, 'infinity'::date) = $1 AND COALESCE(link, '') < $2)" +
+				" OR (COALESCE(release_date, 'infinity'::date) = $1 AND COALESCE(link, '') = $2 AND id > $3))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := SongKeyset(item, tt.sort)
+			if !reflect.DeepEqual(key.Values, tt.wantKey) {
+				t.Fatalf("SongKeyset() = %q, want %q", key.Values, tt.wantKey)
+			}
+
+			var b queryBuilder
+			if err := b.after(withTiebreaker(tt.sort), key); err != nil {
+				t.Fatalf("after: %v", err)
+			}
+			if got := b.whereClause(); got != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got, tt.wantWhere)
+			}
+			if len(b.args) != len(key.Values) {
+				t.Fatalf("args = %v, want %d values", b.args, len(key.Values))
+			}
+			for i, arg := range b.args {
+				if arg != key.Values[i] {
+					t.Errorf("arg $%d = %v, want %q", i+1, arg, key.Values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAfterRejectsForeignKeyset(t *testing.T) {
+	var b queryBuilder
+	err := b.after([]SortField{{Field: "group_name"}, {Field: "id"}}, Keyset{Values: []string{"7"}})
+	if !errors.Is(err, ErrInvalidSort) {
+		t.Errorf("after() error = %v, want ErrInvalidSort", err)
+	}
+}
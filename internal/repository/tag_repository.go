@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"music-test-lib/internal/domain"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("tag already exists")
+)
+
+// TagRepository хранит теги песен.
+type TagRepository struct {
+	db *sqlx.DB
+}
+
+// NewTagRepository создает новый TagRepository.
+func NewTagRepository(db *sqlx.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// tagColumns - столбцы тега в порядке, ожидаемом scanTag.
+const tagColumns = "t.id, t.name, (SELECT count(*) FROM song_tags st WHERE st.tag_id = t.id)"
+
+func scanTag(row rowScanner) (domain.Tag, error) {
+	var tag domain.Tag
+	err := row.Scan(&tag.ID, &tag.Name, &tag.SongCount)
+	return tag, err
+}
+
+// GetTags возвращает страницу тегов, название которых содержит name
+// (если он задан), в алфавитном порядке, и общее количество таких тегов.
+func (r *TagRepository) GetTags(name string, page Pagination) ([]domain.Tag, int, error) {
+	var b queryBuilder
+	b.match("t.name", StringFilter{Value: name, Mode: MatchContains})
+
+	var total int
+	if err := r.db.QueryRow("SELECT count(*) FROM tags t"+b.whereClause(), b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + tagColumns + " FROM tags t" + b.whereClause() +
+		" ORDER BY lower(t.name), t.id LIMIT " + b.arg(page.Limit) + " OFFSET " + b.arg(page.Offset)
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, total, rows.Err()
+}
+
+// GetTagByID возвращает тег по его ID.
+func (r *TagRepository) GetTagByID(id string) (*domain.Tag, error) {
+	tag, err := scanTag(r.db.QueryRow("SELECT "+tagColumns+" FROM tags t WHERE t.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// AddTag добавляет тег. Если тег с таким названием уже есть, возвращает ErrTagExists.
+func (r *TagRepository) AddTag(name string) (*domain.Tag, error) {
+	tag := domain.Tag{Name: name}
+	err := r.db.QueryRow("INSERT INTO tags (name) VALUES ($1) RETURNING id", name).Scan(&tag.ID)
+	if err != nil {
+		return nil, tagError(err)
+	}
+	return &tag, nil
+}
+
+// tagSongIDs - запрос ID песен с тегом $1.
+const tagSongIDs = "SELECT song_id FROM song_tags WHERE tag_id = $1"
+
+// RenameTag переименовывает тег.
+func (r *TagRepository) RenameTag(id, name string) (*domain.Tag, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tag, err := scanTag(tx.QueryRow("UPDATE tags t SET name = $2 WHERE t.id = $1 RETURNING "+tagColumns, id, name))
+	if err == sql.ErrNoRows {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, tagError(err)
+	}
+	if err := touchLinkedSongs(tx, tagSongIDs, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// DeleteTag удаляет тег; песни теряют этот тег.
+func (r *TagRepository) DeleteTag(id string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Версии песен изменяются до удаления, пока связи с тегом ещё есть
+	if err := touchLinkedSongs(tx, tagSongIDs, id); err != nil {
+		return err
+	}
+	res, err := tx.Exec("DELETE FROM tags WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTagNotFound
+	}
+	return tx.Commit()
+}
+
+func tagError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return ErrTagExists
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+)
+
+// AlbumService содержит бизнес-логику для работы с альбомами.
+type AlbumService struct {
+	repo *repository.AlbumRepository
+}
+
+// NewAlbumService создаёт новый экземпляр AlbumService.
+func NewAlbumService(repo *repository.AlbumRepository) *AlbumService {
+	return &AlbumService{repo: repo}
+}
+
+// AlbumList - страница списка альбомов.
+type AlbumList struct {
+	Items []domain.Album
+	Page  int
+	Limit int
+	Total int
+}
+
+// GetAlbums возвращает страницу альбомов, удовлетворяющих фильтру.
+// Нумерация страниц начинается с 1.
+func (s *AlbumService) GetAlbums(filter repository.AlbumFilter, page, limit int) (*AlbumList, error) {
+	albums, total, err := s.repo.GetAlbums(filter, repository.Pagination{Limit: limit, Offset: (page - 1) * limit})
+	if err != nil {
+		return nil, err
+	}
+	return &AlbumList{Items: albums, Page: page, Limit: limit, Total: total}, nil
+}
+
+// GetAlbum возвращает альбом с треками по ID.
+func (s *AlbumService) GetAlbum(id string) (*domain.Album, error) {
+	return s.repo.GetAlbumByID(id)
+}
+
+// AddAlbum добавляет альбом без треков.
+func (s *AlbumService) AddAlbum(in repository.AlbumInput) (*domain.Album, error) {
+	return s.repo.AddAlbum(in)
+}
+
+// UpdateAlbum заменяет данные альбома.
+func (s *AlbumService) UpdateAlbum(id string, in repository.AlbumInput) (*domain.Album, error) {
+	return s.repo.UpdateAlbum(id, in)
+}
+
+// SetAlbumTracks заменяет треки альбома.
+func (s *AlbumService) SetAlbumTracks(id string, tracks []domain.AlbumTrack) (*domain.Album, error) {
+	return s.repo.SetAlbumTracks(id, tracks)
+}
+
+// DeleteAlbum удаляет альбом; песни альбома остаются в библиотеке.
+func (s *AlbumService) DeleteAlbum(id string) error {
+	return s.repo.DeleteAlbum(id)
+}
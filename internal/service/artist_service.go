@@ -0,0 +1,54 @@
+package service
+
+import (
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+)
+
+// ArtistService содержит бизнес-логику для работы с исполнителями.
+type ArtistService struct {
+	repo *repository.ArtistRepository
+}
+
+// NewArtistService создаёт новый экземпляр ArtistService.
+func NewArtistService(repo *repository.ArtistRepository) *ArtistService {
+	return &ArtistService{repo: repo}
+}
+
+// ArtistList - страница списка исполнителей.
+type ArtistList struct {
+	Items []domain.Artist
+	Page  int
+	Limit int
+	Total int
+}
+
+// GetArtists возвращает страницу исполнителей, название которых содержит name.
+// Нумерация страниц начинается с 1.
+func (s *ArtistService) GetArtists(name string, page, limit int) (*ArtistList, error) {
+	artists, total, err := s.repo.GetArtists(name, repository.Pagination{Limit: limit, Offset: (page - 1) * limit})
+	if err != nil {
+		return nil, err
+	}
+	return &ArtistList{Items: artists, Page: page, Limit: limit, Total: total}, nil
+}
+
+// GetArtist возвращает исполнителя по ID.
+func (s *ArtistService) GetArtist(id string) (*domain.Artist, error) {
+	return s.repo.GetArtistByID(id)
+}
+
+// AddArtist добавляет исполнителя.
+func (s *ArtistService) AddArtist(name string) (*domain.Artist, error) {
+	return s.repo.AddArtist(name)
+}
+
+// RenameArtist переименовывает исполнителя; название группы меняется во всех его песнях.
+func (s *ArtistService) RenameArtist(id, name string) (*domain.Artist, error) {
+	return s.repo.RenameArtist(id, name)
+}
+
+// DeleteArtist удаляет исполнителя без песен и альбомов.
+func (s *ArtistService) DeleteArtist(id string) error {
+	return s.repo.DeleteArtist(id)
+}
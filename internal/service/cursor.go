@@ -0,0 +1,72 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"music-test-lib/internal/repository"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// songCursor - содержимое курсора постраничной выборки песен.
+// Курсор привязан к фильтру и сортировке, с которыми он был выдан.
+type songCursor struct {
+	Scope string            `json:"s"`
+	Key   repository.Keyset `json:"k"`
+}
+
+// cursorCodec кодирует курсоры в непрозрачные строки и подписывает их HMAC,
+// чтобы изменённый клиентом курсор не принимался.
+type cursorCodec struct {
+	secret []byte
+}
+
+func (c cursorCodec) encode(cur songCursor) (string, error) {
+	payload, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload)), nil
+}
+
+func (c cursorCodec) decode(s string) (songCursor, error) {
+	var cur songCursor
+	enc := base64.RawURLEncoding
+	payloadPart, sigPart, ok := strings.Cut(s, ".")
+	if !ok {
+		return cur, ErrInvalidCursor
+	}
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return cur, ErrInvalidCursor
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, c.sign(payload)) {
+		return cur, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, &cur); err != nil {
+		return cur, ErrInvalidCursor
+	}
+	return cur, nil
+}
+
+func (c cursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cursorScope возвращает отпечаток параметров выборки, к которым привязывается курсор.
+func cursorScope(filter repository.SongFilter, sort []repository.SortField) string {
+	raw, _ := json.Marshal(struct {
+		Filter repository.SongFilter
+		Sort   string
+	}{filter, repository.FormatSort(sort)})
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"music-test-lib/internal/repository"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	tests := []struct {
+		name string
+		cur  songCursor
+	}{
+		{"id", songCursor{Scope: "scope", Key: repository.Keyset{Values: []string{"42"}}}},
+		{"several fields", songCursor{Scope: "scope", Key: repository.Keyset{Values: []string{"Muse", "infinity", "7"}}}},
+		{"special characters", songCursor{Scope: "", Key: repository.Keyset{Values: []string{"AC/DC \"Live\"", "Кино", "1"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := codec.encode(tt.cur)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			got, err := codec.decode(s)
+			if err != nil {
+				t.Fatalf("decode(%q): %v", s, err)
+			}
+			if !reflect.DeepEqual(got, tt.cur) {
+				t.Errorf("decode(encode(%+v)) = %+v", tt.cur, got)
+			}
+		})
+	}
+}
+
+func TestCursorCodecRejectsInvalid(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	valid, err := codec.encode(songCursor{Scope: "scope", Key: repository.Keyset{Values: []string{"1"}}})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	payload, sig, _ := strings.Cut(valid, ".")
+	enc := base64.RawURLEncoding
+	forged := enc.EncodeToString([]byte(`{"s":"scope","k":{"Values":["2"]}}`))
+	otherSecret, err := cursorCodec{secret: []byte("other")}.encode(songCursor{Scope: "scope", Key: repository.Keyset{Values: []string{"1"}}})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	notJSON := enc.EncodeToString([]byte("not json"))
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"payload is not base64", "!!!." + sig},
+		{"signature is not base64", payload + ".!!!"},
+		{"changed payload", forged + "." + sig},
+		{"changed signature", payload + "." + enc.EncodeToString([]byte("signature"))},
+		{"other secret", otherSecret},
+		{"signed payload is not json", notJSON + "." + enc.EncodeToString(codec.sign([]byte("not json")))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.decode(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decode(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestCursorScope(t *testing.T) {
+	base := repository.SongFilter{Group: repository.StringFilter{Value: "Muse"}}
+	sort := []repository.SortField{{Field: "release_date", Desc: true}}
+	scope := cursorScope(base, sort)
+
+	if got := cursorScope(base, []repository.SortField{{Field: "release_date", Desc: true}}); got != scope {
+		t.Errorf("scope for the same query = %q, want %q", got, scope)
+	}
+	tests := []struct {
+		name   string
+		filter repository.SongFilter
+		sort   []repository.SortField
+	}{
+		{"other filter", repository.SongFilter{Group: repository.StringFilter{Value: "Queen"}}, sort},
+		{"other sort direction", base, []repository.SortField{{Field: "release_date"}}},
+		{"other sort field", base, []repository.SortField{{Field: "song_name", Desc: true}}},
+		{"no sort", base, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cursorScope(tt.filter, tt.sort); got == scope {
+				t.Errorf("cursorScope(%+v, %v) matches scope of another query", tt.filter, tt.sort)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-test-lib/internal/musicinfo"
+	"music-test-lib/internal/repository"
+	"sync"
+	"time"
+)
+
+// EnrichmentConfig - настройки обработки заданий на получение данных о песнях.
+type EnrichmentConfig struct {
+	// Количество параллельных обработчиков
+	Workers int
+	// Интервал опроса очереди, когда готовых заданий нет
+	PollInterval time.Duration
+	// Максимальное число попыток, после которого песня помечается как failed
+	MaxAttempts int
+	// Начальная и максимальная задержка перед повторной попыткой
+	RetryBackoff    time.Duration
+	RetryBackoffMax time.Duration
+	// Время, на которое задание закрепляется за обработчиком
+	Lease time.Duration
+}
+
+// Enricher получает из внешнего API данные песен, добавленных через
+// SongService.AddSongAsync. Задания хранятся в базе данных, поэтому
+// переживают перезапуск сервиса и могут обрабатываться несколькими экземплярами.
+type Enricher struct {
+	jobs      *repository.EnrichmentRepository
+	musicInfo musicinfo.Client
+	cfg       EnrichmentConfig
+	log       *slog.Logger
+}
+
+// NewEnricher создаёт новый Enricher.
+func NewEnricher(jobs *repository.EnrichmentRepository, musicInfo musicinfo.Client, cfg EnrichmentConfig, log *slog.Logger) *Enricher {
+	return &Enricher{jobs: jobs, musicInfo: musicInfo, cfg: cfg, log: log}
+}
+
+// Run запускает обработчики и ждёт их завершения после отмены ctx.
+func (e *Enricher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < e.cfg.Workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			e.work(ctx, e.log.With(slog.Int("worker", worker)))
+		}(i)
+	}
+	wg.Wait()
+}
+
+// work выполняет задания, пока они есть, затем ждёт PollInterval.
+func (e *Enricher) work(ctx context.Context, log *slog.Logger) {
+	for {
+		for ctx.Err() == nil {
+			job, err := e.jobs.Claim(e.cfg.Lease)
+			if err != nil {
+				log.Error("failed to claim enrichment job", slog.Any("error", err))
+				break
+			}
+			if job == nil {
+				break
+			}
+			e.process(ctx, job, log)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.cfg.PollInterval):
+		}
+	}
+}
+
+// process получает данные песни и сохраняет результат задания.
+func (e *Enricher) process(ctx context.Context, job *repository.EnrichmentJob, log *slog.Logger) {
+	log = log.With(slog.String("song_id", job.SongID), slog.Int("attempt", job.Attempts))
+
+	info, err := e.musicInfo.GetInfo(ctx, job.Group, job.Title)
+	if err == nil {
+		song, parseErr := songFromInfo(job.Group, job.Title, info)
+		if parseErr == nil {
+			if err := e.jobs.Complete(job, song); err != nil {
+				log.Error("failed to save enriched song", slog.Any("error", err))
+				return
+			}
+			log.Info("song enriched")
+			return
+		}
+		err = parseErr
+	}
+	if ctx.Err() != nil {
+		// Сервис останавливается - задание будет выполнено снова после истечения Lease
+		return
+	}
+
+	// Песни нет во внешнем API или попытки исчерпаны - повторять бессмысленно
+	if errors.Is(err, musicinfo.ErrNotFound) || job.Attempts >= e.cfg.MaxAttempts {
+		if errors.Is(err, musicinfo.ErrNotFound) {
+			err = fmt.Errorf("песня не найдена во внешнем API: %w", err)
+		}
+		log.Warn("song enrichment failed", slog.Any("error", err))
+		if err := e.jobs.Fail(job, err); err != nil {
+			log.Error("failed to mark enrichment as failed", slog.Any("error", err))
+		}
+		return
+	}
+
+	delay := e.retryDelay(job.Attempts)
+	log.Warn("song enrichment failed, retrying", slog.Duration("delay", delay), slog.Any("error", err))
+	if err := e.jobs.Retry(job, err, time.Now().Add(delay)); err != nil {
+		log.Error("failed to reschedule enrichment job", slog.Any("error", err))
+	}
+}
+
+// retryDelay возвращает задержку после попытки номер attempt:
+// RetryBackoff * 2^(attempt-1), не больше RetryBackoffMax.
+func (e *Enricher) retryDelay(attempt int) time.Duration {
+	delay := e.cfg.RetryBackoff << (attempt - 1)
+	if e.cfg.RetryBackoffMax > 0 && (delay > e.cfg.RetryBackoffMax || delay <= 0) {
+		delay = e.cfg.RetryBackoffMax
+	}
+	return delay
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+)
+
+// GenreService содержит бизнес-логику для работы с жанрами.
+type GenreService struct {
+	repo *repository.GenreRepository
+}
+
+// NewGenreService создаёт новый экземпляр GenreService.
+func NewGenreService(repo *repository.GenreRepository) *GenreService {
+	return &GenreService{repo: repo}
+}
+
+// GetGenres возвращает дерево жанров: жанры верхнего уровня с вложенными поджанрами.
+func (s *GenreService) GetGenres() ([]domain.Genre, error) {
+	genres, err := s.repo.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+	return genreTree(genres, ""), nil
+}
+
+// GetGenre возвращает жанр со всеми его поджанрами.
+func (s *GenreService) GetGenre(id string) (*domain.Genre, error) {
+	genre, err := s.repo.GetGenreByID(id)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := s.repo.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+	genre.Subgenres = genreTree(genres, genre.ID)
+	return genre, nil
+}
+
+// AddGenre добавляет жанр. Пустой parentID означает жанр верхнего уровня.
+func (s *GenreService) AddGenre(name, parentID string) (*domain.Genre, error) {
+	return s.repo.AddGenre(name, parentID)
+}
+
+// UpdateGenre изменяет название и родительский жанр.
+func (s *GenreService) UpdateGenre(id, name, parentID string) (*domain.Genre, error) {
+	return s.repo.UpdateGenre(id, name, parentID)
+}
+
+// DeleteGenre удаляет жанр без поджанров.
+func (s *GenreService) DeleteGenre(id string) error {
+	return s.repo.DeleteGenre(id)
+}
+
+// genreTree возвращает поджанры жанра parentID (при пустом parentID - жанры
+// верхнего уровня) с вложенными поджанрами, сохраняя порядок genres.
+func genreTree(genres []domain.Genre, parentID string) []domain.Genre {
+	children := make(map[string][]domain.Genre)
+	for _, g := range genres {
+		children[g.ParentID] = append(children[g.ParentID], g)
+	}
+	var build func(parentID string) []domain.Genre
+	build = func(parentID string) []domain.Genre {
+		list := children[parentID]
+		for i := range list {
+			list[i].Subgenres = build(list[i].ID)
+		}
+		return list
+	}
+	tree := build(parentID)
+	if tree == nil {
+		return []domain.Genre{}
+	}
+	return tree
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"music-test-lib/internal/repository"
+	"time"
+)
+
+// IdempotencyConfig - настройки хранения ключей идемпотентности.
+type IdempotencyConfig struct {
+	// Время, после которого ключ незавершённого запроса считается брошенным
+	// и может быть занят заново
+	ReserveTimeout time.Duration
+	// Время хранения ключа (0 - хранить бессрочно)
+	TTL time.Duration
+	// Интервал между удалениями истёкших ключей
+	CleanupInterval time.Duration
+}
+
+// IdempotencyCleaner периодически удаляет ключи идемпотентности,
+// занятые раньше, чем IdempotencyConfig.TTL назад.
+type IdempotencyCleaner struct {
+	keys *repository.IdempotencyRepository
+	cfg  IdempotencyConfig
+	log  *slog.Logger
+}
+
+// NewIdempotencyCleaner создаёт новый IdempotencyCleaner.
+func NewIdempotencyCleaner(keys *repository.IdempotencyRepository, cfg IdempotencyConfig, log *slog.Logger) *IdempotencyCleaner {
+	return &IdempotencyCleaner{keys: keys, cfg: cfg, log: log}
+}
+
+// Run удаляет истёкшие ключи каждые CleanupInterval до отмены ctx.
+func (c *IdempotencyCleaner) Run(ctx context.Context) {
+	if c.cfg.TTL <= 0 || c.cfg.CleanupInterval <= 0 {
+		c.log.Info("idempotency keys cleanup is disabled")
+		return
+	}
+	ticker := time.NewTicker(c.cfg.CleanupInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := c.keys.DeleteExpired(c.cfg.TTL)
+		if err != nil {
+			c.log.Error("failed to delete expired idempotency keys", slog.Any("error", err))
+		} else if deleted > 0 {
+			c.log.Info("expired idempotency keys deleted", slog.Int64("deleted", deleted))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+)
+
+// PlaylistService содержит бизнес-логику для работы с плейлистами.
+// Пользователь user - владелец плейлистов, с которыми выполняется действие:
+// чужие приватные плейлисты для него не существуют, а чужие публичные
+// доступны только для чтения и копирования.
+type PlaylistService struct {
+	repo *repository.PlaylistRepository
+}
+
+// NewPlaylistService создаёт новый экземпляр PlaylistService.
+func NewPlaylistService(repo *repository.PlaylistRepository) *PlaylistService {
+	return &PlaylistService{repo: repo}
+}
+
+// PlaylistList - страница списка плейлистов.
+type PlaylistList struct {
+	Items []domain.Playlist
+	Page  int
+	Limit int
+	Total int
+}
+
+// GetPlaylists возвращает страницу доступных пользователю плейлистов.
+// Нумерация страниц начинается с 1.
+func (s *PlaylistService) GetPlaylists(filter repository.PlaylistFilter, page, limit int) (*PlaylistList, error) {
+	playlists, total, err := s.repo.GetPlaylists(filter, repository.Pagination{Limit: limit, Offset: (page - 1) * limit})
+	if err != nil {
+		return nil, err
+	}
+	return &PlaylistList{Items: playlists, Page: page, Limit: limit, Total: total}, nil
+}
+
+// GetPlaylist возвращает плейлист с записями, если он публичный
+// или принадлежит пользователю user.
+func (s *PlaylistService) GetPlaylist(id, user string) (*domain.Playlist, error) {
+	playlist, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !playlist.Public && playlist.Owner != user {
+		return nil, repository.ErrPlaylistNotFound
+	}
+	return playlist, nil
+}
+
+// AddPlaylist добавляет пустой плейлист пользователя user.
+func (s *PlaylistService) AddPlaylist(user string, in repository.PlaylistInput) (*domain.Playlist, error) {
+	return s.repo.AddPlaylist(user, in)
+}
+
+// UpdatePlaylist изменяет название, описание и видимость плейлиста.
+func (s *PlaylistService) UpdatePlaylist(id, user string, in repository.PlaylistInput) (*domain.Playlist, error) {
+	return s.repo.UpdatePlaylist(id, user, in)
+}
+
+// DeletePlaylist удаляет плейлист.
+func (s *PlaylistService) DeletePlaylist(id, user string) error {
+	return s.repo.DeletePlaylist(id, user)
+}
+
+// AddEntry добавляет песню в плейлист на позицию position; 0 - в конец.
+func (s *PlaylistService) AddEntry(id, user, songID string, position int) (*domain.Playlist, error) {
+	return s.repo.AddEntry(id, user, songID, position)
+}
+
+// RemoveEntry удаляет запись из плейлиста.
+func (s *PlaylistService) RemoveEntry(id, user, entryID string) (*domain.Playlist, error) {
+	return s.repo.RemoveEntry(id, user, entryID)
+}
+
+// MoveEntry переносит запись плейлиста на позицию position.
+func (s *PlaylistService) MoveEntry(id, user, entryID string, position int) (*domain.Playlist, error) {
+	return s.repo.MoveEntry(id, user, entryID, position)
+}
+
+// DuplicatePlaylist копирует свой или публичный плейлист в новый приватный
+// плейлист пользователя user. Пустое name - название исходного плейлиста.
+func (s *PlaylistService) DuplicatePlaylist(id, user, name string) (*domain.Playlist, error) {
+	return s.repo.DuplicatePlaylist(id, user, name)
+}
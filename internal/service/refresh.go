@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/musicinfo"
+	"time"
+)
+
+// RefreshResult - результат повторного получения данных песни из внешнего API.
+type RefreshResult struct {
+	// Песня после обновления или, если изменения не применялись, сохранённая песня
+	Song *domain.Song
+	// Поля, значения которых во внешнем API отличаются от сохранённых
+	Changes []domain.FieldChange
+	Applied bool
+}
+
+// RefreshSong заново запрашивает данные песни во внешнем API и сравнивает
+// их с сохранёнными. Если apply = true, полученные данные сохраняются,
+// иначе песня не изменяется.
+func (s *SongService) RefreshSong(ctx context.Context, id string, apply bool) (*RefreshResult, error) {
+	song, err := s.repo.GetSongByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.refresh(ctx, song, apply)
+}
+
+func (s *SongService) refresh(ctx context.Context, song *domain.Song, apply bool) (*RefreshResult, error) {
+	info, err := s.musicInfo.GetInfo(ctx, song.Group, song.Title)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к внешнему API: %w", err)
+	}
+	fresh, err := songFromInfo(song.Group, song.Title, info)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RefreshResult{Song: song, Changes: diffExternalInfo(song, fresh)}
+	if !apply {
+		return result, nil
+	}
+	// Сохраняем и без изменений, чтобы отметить время проверки
+	// и снять с песни ошибку получения данных
+	result.Song, err = s.repo.RefreshSong(song.ID, fresh)
+	if err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// diffExternalInfo возвращает поля, получаемые из внешнего API, которые
+// будут заменены значениями из fresh: по тем же правилам, что и при сохранении,
+// значения, заданные вручную, и пустые значения из внешнего API не учитываются.
+func diffExternalInfo(song *domain.Song, fresh domain.SongWithoutID) []domain.FieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"release_date", song.ReleaseDate, fresh.ReleaseDate},
+		{"lyrics", song.Lyrics, fresh.Lyrics},
+		{"link", song.Link, fresh.Link},
+	}
+	changes := []domain.FieldChange{}
+	for _, f := range fields {
+		if song.ExternalValueApplies(f.name, f.old, f.new) {
+			changes = append(changes, domain.FieldChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// RefreshConfig - настройки периодического обновления данных песен.
+type RefreshConfig struct {
+	// Возраст данных, после которого они запрашиваются заново (0 - не обновлять)
+	MaxAge time.Duration
+	// Интервал между проверками (0 - не обновлять)
+	Interval time.Duration
+	// Максимальное количество песен, обновляемых за одну проверку
+	BatchSize int
+}
+
+// Refresher периодически обновляет данные песен, полученные из внешнего API
+// раньше, чем RefreshConfig.MaxAge назад.
+type Refresher struct {
+	songs *SongService
+	cfg   RefreshConfig
+	log   *slog.Logger
+}
+
+// NewRefresher создаёт новый Refresher.
+func NewRefresher(songs *SongService, cfg RefreshConfig, log *slog.Logger) *Refresher {
+	return &Refresher{songs: songs, cfg: cfg, log: log}
+}
+
+// Run обновляет данные песен каждые Interval до отмены ctx.
+func (r *Refresher) Run(ctx context.Context) {
+	if r.cfg.MaxAge <= 0 || r.cfg.Interval <= 0 {
+		r.log.Info("scheduled refresh is disabled")
+		return
+	}
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		r.refreshStale(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshStale обновляет одну партию устаревших песен.
+func (r *Refresher) refreshStale(ctx context.Context) {
+	songs, err := r.songs.repo.StaleSongs(r.cfg.MaxAge, r.cfg.BatchSize)
+	if err != nil {
+		r.log.Error("failed to get stale songs", slog.Any("error", err))
+		return
+	}
+
+	var refreshed int
+	for i := range songs {
+		song := &songs[i]
+		result, err := r.songs.refresh(ctx, song, true)
+		switch {
+		case err == nil:
+			refreshed++
+			if len(result.Changes) > 0 {
+				r.log.Info("song refreshed", slog.String("song_id", song.ID), slog.Int("changes", len(result.Changes)))
+			}
+			continue
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, musicinfo.ErrUnavailable):
+			// Внешний API недоступен - продолжим на следующей проверке
+			r.log.Warn("music info API is unavailable, refresh postponed")
+			return
+		case !errors.Is(err, musicinfo.ErrNotFound) && !errors.Is(err, ErrInvalidExternalData):
+			// Временная ошибка (ответ 5xx, таймаут, ошибка БД) - песня
+			// останется устаревшей и будет обновлена на следующей проверке
+			r.log.Error("failed to refresh song, retry on next check", slog.String("song_id", song.ID), slog.Any("error", err))
+			continue
+		}
+
+		// Песня не найдена или данные некорректны: оставляем сохранённые данные
+		// и не проверяем песню до следующего срока
+		r.log.Warn("failed to refresh song", slog.String("song_id", song.ID), slog.Any("error", err))
+		if err := r.songs.repo.TouchRefreshed(song.ID); err != nil {
+			r.log.Error("failed to mark song as refreshed", slog.String("song_id", song.ID), slog.Any("error", err))
+		}
+	}
+	if len(songs) > 0 {
+		r.log.Info("stale songs refreshed", slog.Int("total", len(songs)), slog.Int("refreshed", refreshed))
+	}
+}
@@ -1,147 +1,430 @@
 package service
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log/slog"
 	"music-test-lib/internal/domain"
+	"music-test-lib/internal/lyrics"
+	"music-test-lib/internal/musicinfo"
 	"music-test-lib/internal/repository"
-	"net/http"
-	"strconv"
 	"strings"
 	"time"
 )
 
+var (
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was used with another request")
+	ErrRequestInProgress      = errors.New("request with this idempotency key is in progress")
+	ErrVerseOutOfRange        = errors.New("verse number is out of range")
+	ErrInvalidExternalData    = errors.New("music info API returned invalid data")
+)
+
 // SongService содержит бизнес-логику для работы с песнями.
 type SongService struct {
-	repo *repository.SongRepository
-	log  *slog.Logger
+	repo           *repository.SongRepository
+	idempotency    *repository.IdempotencyRepository
+	idempotencyCfg IdempotencyConfig
+	musicInfo      musicinfo.Client
+	log            *slog.Logger
+	cursors        cursorCodec
 }
 
 // NewSongService создаёт новый экземпляр SongService.
-func NewSongService(repo *repository.SongRepository, log *slog.Logger) *SongService {
-	return &SongService{repo: repo, log: log}
+// cursorSecret используется для подписи курсоров постраничной выборки.
+func NewSongService(
+	repo *repository.SongRepository,
+	idempotency *repository.IdempotencyRepository,
+	idempotencyCfg IdempotencyConfig,
+	musicInfo musicinfo.Client,
+	log *slog.Logger,
+	cursorSecret []byte,
+) *SongService {
+	return &SongService{
+		repo:           repo,
+		idempotency:    idempotency,
+		idempotencyCfg: idempotencyCfg,
+		musicInfo:      musicInfo,
+		log:            log,
+		cursors:        cursorCodec{secret: cursorSecret},
+	}
 }
 
-// GetSongs возвращает список песен с фильтрацией.
-func (s *SongService) GetSongs(params map[string][]string) ([]domain.Song, error) {
-	return s.repo.GetSongs(params)
+// SongQuery - параметры выборки списка песен.
+// Если задан Cursor, выборка продолжается с позиции курсора, а Page не учитывается.
+type SongQuery struct {
+	Filter repository.SongFilter
+	Sort   []repository.SortField
+	Page   int
+	Limit  int
+	Cursor string
 }
 
-// GetSongLyrics возвращает текст песни.
-func (s *SongService) GetSongLyrics(id string, verse string) (string, error) {
-	song, err := s.repo.GetSongByID(id)
+// SongList - страница списка песен.
+// NextCursor указывает на следующую страницу и пуст, если страница последняя.
+// DidYouMean содержит исправленные названия группы и песни, если точных
+// совпадений с фильтром нет, а похожие есть.
+type SongList struct {
+	Items      []domain.SongListItem
+	Page       int
+	Limit      int
+	Total      int
+	NextCursor string
+	DidYouMean *domain.SongSuggestion
+}
+
+// TotalPages возвращает общее количество страниц.
+func (l *SongList) TotalPages() int {
+	if l.Limit < 1 {
+		return 0
+	}
+	return (l.Total + l.Limit - 1) / l.Limit
+}
+
+// GetSongs возвращает страницу списка песен с фильтрацией.
+// Нумерация страниц начинается с 1. Курсор, выданный для другого фильтра
+// или сортировки, или изменённый курсор отклоняется с ErrInvalidCursor.
+func (s *SongService) GetSongs(q SongQuery) (*SongList, error) {
+	if len(q.Sort) == 0 {
+		q.Sort = repository.DefaultSort(q.Filter)
+	}
+	scope := cursorScope(q.Filter, q.Sort)
+	// Запрашиваем на одну строку больше, чтобы узнать, есть ли следующая страница
+	page := repository.Pagination{Limit: q.Limit + 1}
+	if q.Cursor != "" {
+		cur, err := s.cursors.decode(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cur.Scope != scope {
+			return nil, ErrInvalidCursor
+		}
+		page.After = &cur.Key
+		q.Page = 0
+	} else {
+		page.Offset = (q.Page - 1) * q.Limit
+	}
+
+	songs, total, err := s.repo.GetSongs(q.Filter, q.Sort, page)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Если необходимый куплет не указан - возвращаем весь текст песни
-	if verse == "" {
-		return song.Lyrics, nil
+	list := &SongList{Items: songs, Page: q.Page, Limit: q.Limit, Total: total}
+	if len(songs) > q.Limit {
+		list.Items = songs[:q.Limit]
+		last := repository.SongKeyset(list.Items[q.Limit-1], q.Sort)
+		list.NextCursor, err = s.cursors.encode(songCursor{Scope: scope, Key: last})
+		if err != nil {
+			return nil, err
+		}
 	}
-	// Разделяем текст песни на куплеты по двум символам новой строки
-	verses := strings.Split(song.Lyrics, "\\\\n\\\\n")
 
-	// Преобразуем параметр verse в int
-	verseIndex, err := strconv.Atoi(verse)
-	if err != nil || verseIndex < 1 || verseIndex > len(verses) {
-		return "", errors.New("некорректный номер куплета")
+	// Подсказку ищем только для первой страницы, когда точных совпадений нет
+	if q.Page == 1 && !hasExactMatch(q.Filter, list.Items) {
+		list.DidYouMean, err = s.suggest(q.Filter)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return list, nil
+}
 
-	// Возвращаем соответствующий куплет (нумерация начинается с 1)
-	return verses[verseIndex-1], nil
+// hasExactMatch сообщает, есть ли среди песен точное (без учёта регистра)
+// совпадение с названиями группы и песни из фильтра.
+// Фильтр без названий считается совпавшим.
+func hasExactMatch(filter repository.SongFilter, items []domain.SongListItem) bool {
+	if filter.Group.Value == "" && filter.Title.Value == "" {
+		return true
+	}
+	if !filter.Fuzzy {
+		return len(items) > 0
+	}
+	for _, item := range items {
+		if (filter.Group.Value == "" || strings.EqualFold(item.Group, filter.Group.Value)) &&
+			(filter.Title.Value == "" || strings.EqualFold(item.Title, filter.Title.Value)) {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *SongService) AddSong(group, songTitle, url string) (*domain.SongWithoutID, error) {
-	// Логика запроса к внешнему API для получения данных о песне
-	apiUrl := fmt.Sprintf("%s?group=%s&song=%s", url, group, songTitle)
-	s.log.Debug("add Song ext url:", apiUrl)
-	resp, err := http.Get(apiUrl)
+// suggest подбирает похожие названия группы и песни для фильтра.
+// Возвращает nil, если исправлять нечего.
+func (s *SongService) suggest(filter repository.SongFilter) (*domain.SongSuggestion, error) {
+	suggestion, err := s.repo.SuggestSong(filter.Group.Value, filter.Title.Value)
 	if err != nil {
-		s.log.Error("Ошибка запроса к внешнему API: ", err)
-		return nil, fmt.Errorf("ошибка запроса к внешнему API: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("внешний API вернул статус: %v", resp.StatusCode)
+	if strings.EqualFold(suggestion.Group, filter.Group.Value) {
+		suggestion.Group = ""
+	}
+	if strings.EqualFold(suggestion.Title, filter.Title.Value) {
+		suggestion.Title = ""
 	}
-	// Чтение данных с API
-	var externalSong struct {
-		ReleaseDate string `json:"releaseDate"`
-		Text        string `json:"text"`
-		Link        string `json:"link"`
+	if suggestion == (domain.SongSuggestion{}) {
+		return nil, nil
 	}
+	return &suggestion, nil
+}
 
-	/* Тест данных из api по описанному сваггеру */
-	//externalSong.ReleaseDate = "16.07.2006"
-	//externalSong.Text = "Ooh baby, don't you know I suffer?\\nOoh baby, can you hear me moan?\\nYou caught me under false pretenses\\nHow long before you let me go?\\n\\nOoh\\nYou set my soul alight\\nOoh\\nYou set my soul alight"
-	//externalSong.Link = "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
-	//
+// GetSong возвращает песню по её ID.
+func (s *SongService) GetSong(id string) (*domain.Song, error) {
+	return s.repo.GetSongByID(id)
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&externalSong)
-	s.log.Info(externalSong.ReleaseDate, externalSong.Text, externalSong.Link)
+// VersePage - страница текста песни: не более PerPage строф, начиная со строфы Verse.
+type VersePage struct {
+	Song    *domain.Song
+	Verse   int
+	PerPage int
+	Total   int
+	Stanzas []domain.Stanza
+}
+
+// GetSongVersePage возвращает песню и perPage строф её текста, начиная
+// со строфы номер verse (нумерация с 1). Если строфы с таким номером нет,
+// возвращает ErrVerseOutOfRange; у песни без текста допустим только verse = 1.
+func (s *SongService) GetSongVersePage(id string, verse, perPage int) (*VersePage, error) {
+	song, err := s.GetSongVerses(id)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось декодировать ответ API: %v", err)
+		return nil, err
+	}
+	total := len(song.Stanzas)
+	if verse < 1 || verse > max(total, 1) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrVerseOutOfRange, verse, total)
 	}
-	// Парсинг даты для БД
-	parseDate, err := time.Parse("02.01.2006", externalSong.ReleaseDate)
+	end := min(verse-1+perPage, total)
+	return &VersePage{
+		Song:    song,
+		Verse:   verse,
+		PerPage: perPage,
+		Total:   total,
+		Stanzas: song.Stanzas[min(verse-1, total):end],
+	}, nil
+}
+
+// GetSongVerses возвращает песню с текстом, разобранным на строфы.
+// Тексты, сохранённые до появления разбора, разбираются при первом обращении.
+func (s *SongService) GetSongVerses(id string) (*domain.Song, error) {
+	song, err := s.repo.GetSongStanzas(id)
 	if err != nil {
-		s.log.Error("Не правильная дата: ", err)
 		return nil, err
 	}
+	if song.Stanzas == nil {
+		song.Stanzas = lyrics.Parse(song.Lyrics)
+		if err := s.repo.SaveStanzas(id, song.Lyrics, song.Stanzas); err != nil {
+			s.log.Error("failed to save stanzas", slog.String("song_id", id), slog.Any("error", err))
+		}
+	}
+	return song, nil
+}
 
-	// Формируем объект песни для сохранения
-	newSong := &domain.SongWithoutID{
-		Group:       group,
-		Title:       songTitle,
-		Lyrics:      externalSong.Text,
-		ReleaseDate: parseDate.Format("2006-01-02"),
-		Link:        externalSong.Link,
+// AddSong получает данные о песне из внешнего API, сохраняет песню
+// и возвращает её с присвоенным ID.
+// Если такая песня уже есть, возвращает *repository.DuplicateError,
+// не обращаясь к внешнему API.
+func (s *SongService) AddSong(ctx context.Context, group, songTitle string) (*domain.Song, error) {
+	existingID, err := s.repo.FindSongByName(group, songTitle)
+	if err == nil {
+		return nil, &repository.DuplicateError{ExistingID: existingID}
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	// Получаем данные о песне из внешнего API
+	externalSong, err := s.musicInfo.GetInfo(ctx, group, songTitle)
+	if err != nil {
+		s.log.Error("Ошибка запроса к внешнему API", slog.Any("error", err))
+		return nil, fmt.Errorf("ошибка запроса к внешнему API: %w", err)
+	}
+	s.log.Info("external song info",
+		slog.String("release_date", externalSong.ReleaseDate),
+		slog.String("link", externalSong.Link),
+	)
+	newSong, err := songFromInfo(group, songTitle, externalSong)
+	if err != nil {
+		s.log.Error("Не правильная дата", slog.Any("error", err))
+		return nil, err
 	}
 
 	// Сохраняем песню в базу данных
-	if err := s.repo.AddSong(*newSong); err != nil {
-		return nil, fmt.Errorf("ошибка сохранения песни в базе данных: %v", err)
+	created, err := s.repo.AddSong(newSong)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения песни в базе данных: %w", err)
 	}
 
-	return newSong, nil
+	return created, nil
 }
 
-// UpdateSong обновляет данные песни.
-func (s *SongService) UpdateSong(id string, updates map[string]string) error {
-	// Получить текущие данные песни
-	song, err := s.repo.GetSongByID(id)
+// songFromInfo формирует песню из сведений внешнего API.
+// Пустая дата релиза допускается: её может не знать ни один источник.
+func songFromInfo(group, songTitle string, info *musicinfo.SongInfo) (domain.SongWithoutID, error) {
+	var releaseDate string
+	if info.ReleaseDate != "" {
+		// Парсинг даты для БД
+		parseDate, err := time.Parse("02.01.2006", info.ReleaseDate)
+		if err != nil {
+			return domain.SongWithoutID{}, fmt.Errorf("%w: некорректная дата релиза: %w", ErrInvalidExternalData, err)
+		}
+		releaseDate = parseDate.Format(repository.DateLayout)
+	}
+	return domain.SongWithoutID{
+		Group:       group,
+		Title:       songTitle,
+		Lyrics:      lyrics.Normalize(info.Text),
+		ReleaseDate: releaseDate,
+		Link:        info.Link,
+		Sources:     info.Sources,
+		Stanzas:     lyrics.Parse(info.Text),
+	}, nil
+}
+
+// AddSongAsync добавляет песню, не дожидаясь ответа внешнего API: песня
+// сохраняется в состоянии domain.EnrichmentPending, а дата релиза, текст
+// и ссылка заполняются позже обработчиками Enricher.
+func (s *SongService) AddSongAsync(group, songTitle string) (*domain.Song, error) {
+	created, err := s.repo.AddPendingSong(group, songTitle)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return repository.ErrNotFound
+		return nil, fmt.Errorf("ошибка сохранения песни в базе данных: %w", err)
+	}
+	return created, nil
+}
+
+// AddSongIdempotent добавляет песню так же, как AddSong, но не более одного
+// раза для одного ключа идемпотентности: повторный запрос с тем же ключом
+// возвращает ранее добавленную песню и replayed = true без обращения к внешнему API.
+// Ключ, использованный с другими группой или названием, отклоняется с
+// ErrIdempotencyKeyMismatch, а ключ ещё выполняющегося запроса - с ErrRequestInProgress.
+// Брошенные и истёкшие ключи (см. IdempotencyConfig) используются заново.
+// Если async = true, песня добавляется так же, как AddSongAsync.
+func (s *SongService) AddSongIdempotent(ctx context.Context, key, group, songTitle string, async bool) (song *domain.Song, replayed bool, err error) {
+	requestHash := idempotencyRequestHash(group, songTitle)
+
+	record, err := s.idempotency.Reserve(key, requestHash, s.idempotencyCfg.ReserveTimeout, s.idempotencyCfg.TTL)
+	if errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		songID, err := replayedSongID(record, requestHash)
+		if err != nil {
+			return nil, false, err
 		}
-		return err
+		song, err := s.repo.GetSongByID(songID)
+		return song, true, err
+	} else if err != nil {
+		return nil, false, err
 	}
 
-	// Обновить необходимые поля
-	if group, ok := updates["group_name"]; ok {
-		song.Group = group
+	if async {
+		song, err = s.AddSongAsync(group, songTitle)
+	} else {
+		song, err = s.AddSong(ctx, group, songTitle)
 	}
-	if title, ok := updates["song_name"]; ok {
-		song.Title = title
+	if err != nil {
+		if releaseErr := s.idempotency.Release(key, record.ReservedAt); releaseErr != nil {
+			s.log.Error("failed to release idempotency key", slog.String("key", key), slog.Any("error", releaseErr))
+		}
+		return nil, false, err
 	}
-	if lyrics, ok := updates["lyrics"]; ok {
-		song.Lyrics = lyrics
+	if err := s.idempotency.Complete(key, record.ReservedAt, song.ID); err != nil {
+		s.log.Error("failed to complete idempotency key", slog.String("key", key), slog.Any("error", err))
 	}
-	if releaseDate, ok := updates["release_date"]; ok {
-		song.ReleaseDate = releaseDate
+	return song, false, nil
+}
+
+// idempotencyRequestHash возвращает отпечаток данных запроса на добавление песни,
+// с которым сверяется повторный запрос с тем же ключом идемпотентности.
+func idempotencyRequestHash(group, songTitle string) string {
+	hash := sha256.Sum256([]byte(group + "\x00" + songTitle))
+	return hex.EncodeToString(hash[:])
+}
+
+// replayedSongID решает, можно ли ответить на повторный запрос сохранённым результатом:
+// возвращает ID ранее добавленной песни, ErrIdempotencyKeyMismatch, если ключ
+// использован с другими данными, или ErrRequestInProgress, если запрос ещё выполняется.
+func replayedSongID(record *repository.IdempotencyKey, requestHash string) (string, error) {
+	if record.RequestHash != requestHash {
+		return "", ErrIdempotencyKeyMismatch
 	}
-	if link, ok := updates["link"]; ok {
-		song.Link = link
+	if record.SongID == "" {
+		return "", ErrRequestInProgress
 	}
+	return record.SongID, nil
+}
 
-	// Сохранить обновлённую песню в базе данных
-	return s.repo.UpdateSong(*song)
+// UpdateSong обновляет данные песни и возвращает обновлённую песню.
+// Если versions не nil, песня изменяется, только если её версия есть в списке.
+func (s *SongService) UpdateSong(id string, upd repository.SongUpdate, versions []int64) (*domain.Song, error) {
+	if upd.Lyrics != nil {
+		text := lyrics.Normalize(*upd.Lyrics)
+		upd.Lyrics = &text
+		upd.Stanzas = lyrics.Parse(text)
+	}
+	return s.repo.UpdateSong(id, upd, versions)
+}
+
+// SetSongCredits заменяет участников песни и возвращает песню.
+// Если versions не nil, песня изменяется, только если её версия есть в списке.
+func (s *SongService) SetSongCredits(id string, credits []domain.Credit, versions []int64) (*domain.Song, error) {
+	return s.repo.SetSongCredits(id, credits, versions)
+}
+
+// SetSongGenres заменяет жанры песни и возвращает песню.
+// Если versions не nil, песня изменяется, только если её версия есть в списке.
+func (s *SongService) SetSongGenres(id string, genreIDs []string, versions []int64) (*domain.Song, error) {
+	return s.repo.SetSongGenres(id, genreIDs, versions)
+}
+
+// SetSongTags заменяет теги песни и возвращает песню; отсутствующие теги создаются.
+// Если versions не nil, песня изменяется, только если её версия есть в списке.
+func (s *SongService) SetSongTags(id string, tags []string, versions []int64) (*domain.Song, error) {
+	return s.repo.SetSongTags(id, tags, versions)
 }
 
 // DeleteSong удаляет песню.
-func (s *SongService) DeleteSong(id string) error {
-	return s.repo.DeleteSong(id)
+// Если versions не nil, песня удаляется, только если её версия есть в списке.
+func (s *SongService) DeleteSong(id string, versions []int64) error {
+	return s.repo.DeleteSong(id, versions)
+}
+
+// DeleteResult - результат удаления одной песни при массовом удалении.
+type DeleteResult struct {
+	ID      int64
+	Deleted bool
+	// Песня не удалена, потому что её версия отличается от указанной
+	VersionMismatch bool
+}
+
+// DeleteSongs удаляет песни с указанными ID и сообщает результат для каждого ID
+// в порядке запроса. Повторяющиеся ID учитываются один раз.
+// Песня, для ID которой в versions указана версия, удаляется, только если
+// её версия совпадает.
+func (s *SongService) DeleteSongs(ids []int64, versions map[int64]int64) ([]DeleteResult, error) {
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	deleted, mismatched, err := s.repo.DeleteSongs(unique, versions)
+	if err != nil {
+		return nil, err
+	}
+	isDeleted := make(map[int64]bool, len(deleted))
+	for _, id := range deleted {
+		isDeleted[id] = true
+	}
+	isMismatched := make(map[int64]bool, len(mismatched))
+	for _, id := range mismatched {
+		isMismatched[id] = true
+	}
+
+	results := make([]DeleteResult, len(unique))
+	for i, id := range unique {
+		results[i] = DeleteResult{ID: id, Deleted: isDeleted[id], VersionMismatch: isMismatched[id]}
+	}
+	return results, nil
 }
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"music-test-lib/internal/repository"
+	"testing"
+)
+
+func TestIdempotencyRequestHash(t *testing.T) {
+	hash := idempotencyRequestHash("Muse", "Uprising")
+	if len(hash) != 64 {
+		t.Fatalf("hash length = %d, want 64 hex characters", len(hash))
+	}
+	if got := idempotencyRequestHash("Muse", "Uprising"); got != hash {
+		t.Errorf("hash of the same request = %q, want %q", got, hash)
+	}
+	tests := []struct {
+		name  string
+		group string
+		title string
+	}{
+		{"other title", "Muse", "Starlight"},
+		{"other group", "Queen", "Uprising"},
+		{"swapped fields", "Uprising", "Muse"},
+		{"shifted separator", "MuseU", "prising"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotencyRequestHash(tt.group, tt.title); got == hash {
+				t.Errorf("idempotencyRequestHash(%q, %q) matches hash of another request", tt.group, tt.title)
+			}
+		})
+	}
+}
+
+func TestReplayedSongID(t *testing.T) {
+	hash := idempotencyRequestHash("Muse", "Uprising")
+	tests := []struct {
+		name    string
+		record  repository.IdempotencyKey
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "completed request",
+			record: repository.IdempotencyKey{Key: "k", RequestHash: hash, SongID: "7"},
+			want:   "7",
+		},
+		{
+			name:    "request in progress",
+			record:  repository.IdempotencyKey{Key: "k", RequestHash: hash},
+			wantErr: ErrRequestInProgress,
+		},
+		{
+			name:    "other request",
+			record:  repository.IdempotencyKey{Key: "k", RequestHash: idempotencyRequestHash("Muse", "Starlight"), SongID: "7"},
+			wantErr: ErrIdempotencyKeyMismatch,
+		},
+		{
+			name:    "other request in progress",
+			record:  repository.IdempotencyKey{Key: "k", RequestHash: idempotencyRequestHash("Muse", "Starlight")},
+			wantErr: ErrIdempotencyKeyMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replayedSongID(&tt.record, hash)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("replayedSongID() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("replayedSongID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"music-test-lib/internal/domain"
+	"music-test-lib/internal/repository"
+)
+
+// TagService содержит бизнес-логику для работы с тегами.
+type TagService struct {
+	repo *repository.TagRepository
+}
+
+// NewTagService создаёт новый экземпляр TagService.
+func NewTagService(repo *repository.TagRepository) *TagService {
+	return &TagService{repo: repo}
+}
+
+// TagList - страница списка тегов.
+type TagList struct {
+	Items []domain.Tag
+	Page  int
+	Limit int
+	Total int
+}
+
+// GetTags возвращает страницу тегов, название которых содержит name.
+// Нумерация страниц начинается с 1.
+func (s *TagService) GetTags(name string, page, limit int) (*TagList, error) {
+	tags, total, err := s.repo.GetTags(name, repository.Pagination{Limit: limit, Offset: (page - 1) * limit})
+	if err != nil {
+		return nil, err
+	}
+	return &TagList{Items: tags, Page: page, Limit: limit, Total: total}, nil
+}
+
+// AddTag добавляет тег.
+func (s *TagService) AddTag(name string) (*domain.Tag, error) {
+	return s.repo.AddTag(name)
+}
+
+// RenameTag переименовывает тег.
+func (s *TagService) RenameTag(id, name string) (*domain.Tag, error) {
+	return s.repo.RenameTag(id, name)
+}
+
+// DeleteTag удаляет тег.
+func (s *TagService) DeleteTag(id string) error {
+	return s.repo.DeleteTag(id)
+}
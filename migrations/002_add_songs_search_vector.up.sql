@@ -0,0 +1,8 @@
+ALTER TABLE songs
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(group_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(song_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(lyrics, '')), 'B')
+    ) STORED;
+
+CREATE INDEX songs_search_vector_idx ON songs USING GIN (search_vector);
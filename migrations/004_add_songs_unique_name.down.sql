@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS songs_normalized_name_key;
+DROP FUNCTION IF EXISTS normalize_name(TEXT);
@@ -0,0 +1,35 @@
+-- Нормализованное название: без учёта регистра, пробелов по краям и повторных пробелов
+CREATE FUNCTION normalize_name(name TEXT) RETURNS TEXT
+    LANGUAGE sql
+    IMMUTABLE
+    STRICT
+    PARALLEL SAFE
+AS
+$$
+SELECT lower(btrim(regexp_replace(name, '\s+', ' ', 'g')))
+$$;
+
+-- Дубликаты не удаляются автоматически: песни могут отличаться текстом,
+-- датой релиза и ссылкой. Если они есть, миграция прерывается, а дубликаты
+-- нужно объединить вручную
+DO
+$$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(format('%s - %s (id: %s)', group_name, song_name, ids), '; ')
+    INTO duplicates
+    FROM (SELECT min(group_name)                        AS group_name,
+                 min(song_name)                         AS song_name,
+                 string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+          FROM songs
+          GROUP BY normalize_name(group_name), normalize_name(song_name)
+          HAVING count(*) > 1) d;
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'в таблице songs есть песни с совпадающими названиями: %', duplicates
+            USING HINT = 'объедините или удалите дубликаты и повторите миграцию (migrate force 3)';
+    END IF;
+END;
+$$;
+
+CREATE UNIQUE INDEX songs_normalized_name_key ON songs (normalize_name(group_name), normalize_name(song_name));
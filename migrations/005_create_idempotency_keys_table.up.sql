@@ -0,0 +1,12 @@
+-- Ключи идемпотентности запросов на добавление песни.
+-- song_id пуст, пока запрос с ключом выполняется.
+CREATE TABLE idempotency_keys
+(
+    key          VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64)    NOT NULL,
+    song_id      INTEGER REFERENCES songs (id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Для удаления истёкших ключей
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS enrichment_jobs;
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS enrichment_error,
+    DROP COLUMN IF EXISTS enrichment_status;
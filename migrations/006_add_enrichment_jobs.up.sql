@@ -0,0 +1,18 @@
+ALTER TABLE songs
+    ADD COLUMN enrichment_status VARCHAR(16) NOT NULL DEFAULT 'ready'
+        CHECK (enrichment_status IN ('pending', 'ready', 'failed')),
+    ADD COLUMN enrichment_error  TEXT;
+
+-- Задания на получение данных о песне из внешнего API.
+-- Задание удаляется после успешного выполнения или окончательной ошибки.
+CREATE TABLE enrichment_jobs
+(
+    id          SERIAL PRIMARY KEY,
+    song_id     INTEGER     NOT NULL UNIQUE REFERENCES songs (id) ON DELETE CASCADE,
+    attempts    INTEGER     NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX enrichment_jobs_next_run_at_idx ON enrichment_jobs (next_run_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS songs_refreshed_at_idx;
+ALTER TABLE songs DROP COLUMN IF EXISTS refreshed_at;
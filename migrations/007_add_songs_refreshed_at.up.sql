@@ -0,0 +1,6 @@
+-- Время последнего получения данных песни из внешнего API.
+-- У существующих песен оно неизвестно (NULL), поэтому они обновятся первыми.
+ALTER TABLE songs ADD COLUMN refreshed_at TIMESTAMPTZ;
+ALTER TABLE songs ALTER COLUMN refreshed_at SET DEFAULT now();
+
+CREATE INDEX songs_refreshed_at_idx ON songs (refreshed_at NULLS FIRST, id);
@@ -0,0 +1,3 @@
+-- Источник каждого поля, полученного из внешних источников: {"lyrics": "files", ...}.
+-- Поле, изменённое вручную, из объекта удаляется.
+ALTER TABLE songs ADD COLUMN sources JSONB NOT NULL DEFAULT '{}';
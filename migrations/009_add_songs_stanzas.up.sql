@@ -0,0 +1,9 @@
+-- Переводы строк в текстах, сохранённых escape-последовательностями (\n, \r\n)
+-- или в формате CRLF, приводятся к обычным.
+UPDATE songs
+SET lyrics = replace(replace(regexp_replace(lyrics, '(\\+r)?\\+n', E'\n', 'g'), E'\r\n', E'\n'), E'\r', E'\n')
+WHERE lyrics ~ '\\+n|\r';
+
+-- Текст песни, разобранный на строфы. NULL - текст ещё не разобран,
+-- он будет разобран при первом обращении к строфам.
+ALTER TABLE songs ADD COLUMN stanzas JSONB;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS songs_updated_at ON songs;
+DROP FUNCTION IF EXISTS songs_set_updated_at();
+ALTER TABLE songs DROP COLUMN IF EXISTS updated_at;
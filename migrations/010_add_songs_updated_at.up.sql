@@ -0,0 +1,23 @@
+-- Время последнего изменения данных песни (для Last-Modified).
+-- Служебные столбцы (stanzas, refreshed_at) на него не влияют.
+ALTER TABLE songs ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE FUNCTION songs_set_updated_at() RETURNS trigger AS
+$$
+BEGIN
+    IF (NEW.group_name, NEW.song_name, NEW.release_date, NEW.lyrics, NEW.link,
+        NEW.enrichment_status, NEW.enrichment_error, NEW.sources)
+        IS DISTINCT FROM
+       (OLD.group_name, OLD.song_name, OLD.release_date, OLD.lyrics, OLD.link,
+        OLD.enrichment_status, OLD.enrichment_error, OLD.sources) THEN
+        NEW.updated_at = now();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_updated_at
+    BEFORE UPDATE
+    ON songs
+    FOR EACH ROW
+EXECUTE FUNCTION songs_set_updated_at();
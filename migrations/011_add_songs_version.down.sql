@@ -0,0 +1,15 @@
+CREATE OR REPLACE FUNCTION songs_set_updated_at() RETURNS trigger AS
+$$
+BEGIN
+    IF (NEW.group_name, NEW.song_name, NEW.release_date, NEW.lyrics, NEW.link,
+        NEW.enrichment_status, NEW.enrichment_error, NEW.sources)
+        IS DISTINCT FROM
+       (OLD.group_name, OLD.song_name, OLD.release_date, OLD.lyrics, OLD.link,
+        OLD.enrichment_status, OLD.enrichment_error, OLD.sources) THEN
+        NEW.updated_at = now();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS version;
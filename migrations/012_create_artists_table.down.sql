@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS songs_artist ON songs;
+DROP FUNCTION IF EXISTS songs_set_artist();
+ALTER TABLE songs DROP COLUMN IF EXISTS artist_id;
+DROP TABLE IF EXISTS artists;
@@ -0,0 +1,49 @@
+CREATE TABLE artists
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX artists_normalized_name_key ON artists (normalize_name(name));
+
+-- Исполнители из существующих песен: для каждого нормализованного названия
+-- берётся написание из самой ранней песни
+INSERT INTO artists (name)
+SELECT DISTINCT ON (normalize_name(group_name)) btrim(group_name)
+FROM songs
+ORDER BY normalize_name(group_name), id;
+
+ALTER TABLE songs ADD COLUMN artist_id INTEGER REFERENCES artists (id) ON DELETE RESTRICT;
+
+UPDATE songs s
+SET artist_id = a.id
+FROM artists a
+WHERE normalize_name(a.name) = normalize_name(s.group_name);
+
+ALTER TABLE songs ALTER COLUMN artist_id SET NOT NULL;
+CREATE INDEX songs_artist_id_idx ON songs (artist_id);
+
+-- group_name остаётся в songs (для поиска и совместимости ответов API),
+-- а artist_id определяется по нему: если исполнителя с таким названием нет,
+-- он создаётся. При переименовании исполнителя group_name его песен
+-- обновляется вместе с ним.
+CREATE FUNCTION songs_set_artist() RETURNS trigger AS
+$$
+BEGIN
+    SELECT id INTO NEW.artist_id FROM artists WHERE normalize_name(name) = normalize_name(NEW.group_name);
+    IF NEW.artist_id IS NULL THEN
+        INSERT INTO artists (name)
+        VALUES (btrim(NEW.group_name))
+        ON CONFLICT ((normalize_name(name))) DO NOTHING;
+        SELECT id INTO NEW.artist_id FROM artists WHERE normalize_name(name) = normalize_name(NEW.group_name);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_artist
+    BEFORE INSERT OR UPDATE OF group_name
+    ON songs
+    FOR EACH ROW
+EXECUTE FUNCTION songs_set_artist();
@@ -0,0 +1,25 @@
+CREATE TABLE albums
+(
+    id           SERIAL PRIMARY KEY,
+    title        VARCHAR(100) NOT NULL,
+    artist_id    INTEGER      NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    release_date DATE,
+    cover_url    VARCHAR(255),
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX albums_artist_id_idx ON albums (artist_id);
+
+-- Треки альбома: песня может входить в несколько альбомов (сборники,
+-- переиздания), но в одном альбоме - только один раз и на одной позиции
+CREATE TABLE album_tracks
+(
+    album_id     INTEGER NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    song_id      INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    disc_number  INTEGER NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number INTEGER NOT NULL CHECK (track_number > 0),
+    PRIMARY KEY (album_id, song_id),
+    UNIQUE (album_id, disc_number, track_number)
+);
+
+CREATE INDEX album_tracks_song_id_idx ON album_tracks (song_id);
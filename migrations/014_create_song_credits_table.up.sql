@@ -0,0 +1,15 @@
+-- Участие исполнителей в песне с ролями. Основной исполнитель песни
+-- (songs.artist_id) в таблицу не записывается: он всегда считается
+-- исполнителем с ролью primary. Здесь хранятся остальные участники,
+-- в том числе дополнительные основные исполнители (дуэты).
+CREATE TABLE song_credits
+(
+    song_id   INTEGER     NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    artist_id INTEGER     NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    role      VARCHAR(20) NOT NULL CHECK (role IN ('primary', 'featured', 'composer', 'lyricist', 'producer')),
+    -- Порядок участников с одной ролью, например "feat. A & B"
+    position  INTEGER     NOT NULL DEFAULT 0,
+    PRIMARY KEY (song_id, artist_id, role)
+);
+
+CREATE INDEX song_credits_artist_id_idx ON song_credits (artist_id, role);
@@ -0,0 +1,41 @@
+-- Жанры образуют дерево: у поджанра есть родительский жанр,
+-- например Rock > Alternative Rock
+CREATE TABLE genres
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    parent_id  INTEGER REFERENCES genres (id) ON DELETE RESTRICT,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX genres_normalized_name_key ON genres (normalize_name(name));
+CREATE INDEX genres_parent_id_idx ON genres (parent_id);
+
+CREATE TABLE song_genres
+(
+    song_id  INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    genre_id INTEGER NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX song_genres_genre_id_idx ON song_genres (genre_id);
+
+-- Теги задаются пользователями произвольно
+CREATE TABLE tags
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX tags_normalized_name_key ON tags (normalize_name(name));
+
+CREATE TABLE song_tags
+(
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    tag_id  INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX song_tags_tag_id_idx ON song_tags (tag_id);
@@ -0,0 +1,28 @@
+CREATE TABLE playlists
+(
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    -- Идентификатор пользователя-владельца (заголовок X-User-ID)
+    owner       VARCHAR(100) NOT NULL,
+    is_public   BOOLEAN      NOT NULL DEFAULT false,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX playlists_owner_idx ON playlists (owner);
+
+-- Песня может входить в плейлист несколько раз, поэтому у записи свой ID.
+-- Уникальность позиции проверяется в конце транзакции: при перестановке
+-- позиции записей временно совпадают.
+CREATE TABLE playlist_entries
+(
+    id          SERIAL PRIMARY KEY,
+    playlist_id INTEGER     NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    song_id     INTEGER     NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position    INTEGER     NOT NULL,
+    added_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT playlist_entries_position_key UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX playlist_entries_song_id_idx ON playlist_entries (song_id);
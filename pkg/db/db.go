@@ -27,7 +27,7 @@ func NewDBConfig(host string, port string, user string, password string, name st
 func Connect(cfg *Config, log *slog.Logger) (*sqlx.DB, error) {
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name)
-	log.Debug("psqlInfo: ", psqlInfo)
+	log.Debug("connecting to database", slog.String("host", cfg.Host), slog.String("db", cfg.Name))
 	db, err := sqlx.Connect("postgres", psqlInfo)
 	if err != nil {
 		return nil, err
@@ -41,11 +41,10 @@ func Connect(cfg *Config, log *slog.Logger) (*sqlx.DB, error) {
 func Migrate(cfg *Config, fileUrl string, log *slog.Logger) error {
 	m, err := migrate.New(fileUrl, fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		cfg.Name, cfg.Password, cfg.Host, cfg.Port, cfg.Name))
-	log.Debug("migrate: ", m)
 	if err != nil {
 		return err
 	}
-	log.Debug("migrate no error: ", m)
+	log.Debug("migrate instance created", slog.String("source", fileUrl))
 	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
 		return err
 	}